@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daltonalley/deckforge-cli/internal/pdf"
+	"github.com/urfave/cli/v3"
+)
+
+// calibrateCommand produces a duplex test sheet for measuring printer offsets
+func calibrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "calibrate",
+		Usage: "Generate a duplex calibration sheet for measuring printer offsets",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   "calibration.pdf",
+				Usage:   "Output PDF filename",
+			},
+			&cli.StringFlag{
+				Name:  "page-size",
+				Value: pdf.PageSizeLetter.Name,
+				Usage: "Page size: letter, a4, a3, tabloid or WIDTHxHEIGHT in mm",
+			},
+		}, calibrationFlags()...),
+		Action: runCalibrate,
+	}
+}
+
+// calibrationFlags are the printer correction flags shared by generation and calibration
+func calibrationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.FloatFlag{
+			Name:  "offset-x",
+			Usage: "Horizontal printer offset correction in mm applied to every page",
+		},
+		&cli.FloatFlag{
+			Name:  "offset-y",
+			Usage: "Vertical printer offset correction in mm applied to every page",
+		},
+		&cli.FloatFlag{
+			Name:  "back-offset-x",
+			Usage: "Additional horizontal correction in mm for the backs of duplex cards",
+		},
+		&cli.FloatFlag{
+			Name:  "back-offset-y",
+			Usage: "Additional vertical correction in mm for the backs of duplex cards",
+		},
+		&cli.FloatFlag{
+			Name:  "scale-x",
+			Value: 1.0,
+			Usage: "Horizontal scale correction (e.g. 1.01 when 100mm prints as 99mm)",
+		},
+		&cli.FloatFlag{
+			Name:  "scale-y",
+			Value: 1.0,
+			Usage: "Vertical scale correction",
+		},
+	}
+}
+
+// calibrationFromFlags reads the printer correction flags
+func calibrationFromFlags(cmd *cli.Command) (pdf.Calibration, error) {
+	calibration := pdf.Calibration{
+		OffsetX:     cmd.Float("offset-x"),
+		OffsetY:     cmd.Float("offset-y"),
+		BackOffsetX: cmd.Float("back-offset-x"),
+		BackOffsetY: cmd.Float("back-offset-y"),
+		ScaleX:      cmd.Float("scale-x"),
+		ScaleY:      cmd.Float("scale-y"),
+	}
+	if err := calibration.Validate(); err != nil {
+		return pdf.Calibration{}, invalidInput(err)
+	}
+	return calibration, nil
+}
+
+func runCalibrate(ctx context.Context, cmd *cli.Command) error {
+	pageSize, err := pdf.ParsePageSize(cmd.String("page-size"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	calibration, err := calibrationFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputPath := cmd.String("output")
+	pdfGen := pdf.NewGenerator(0)
+	pdfGen.SetOutputPath(outputPath)
+	pdfGen.SetPageSize(pageSize)
+	pdfGen.SetCalibration(calibration)
+
+	if err := pdfGen.GenerateCalibration(); err != nil {
+		return fmt.Errorf("failed to generate calibration sheet: %w", err)
+	}
+
+	if !cmd.Bool("quiet") {
+		fmt.Printf("✅ Calibration sheet written to '%s'\n", outputPath)
+		fmt.Println("   Print it double-sided, then measure how far the red (back) marks sit from the black (front) marks.")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/legality"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/urfave/cli/v3"
+)
+
+// checkCommand checks a decklist against a format's legality rules
+func checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     "Check a decklist is legal in a format: banned and restricted cards, copy limits and deck size",
+		ArgsUsage: "<decklist.csv>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "legality",
+				Required: true,
+				Usage:    "Format to check: " + strings.Join(legality.Names(), ", "),
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: resolve.DefaultConcurrency,
+				Usage: "Number of cards fetched in parallel",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the result as JSON",
+			},
+		},
+		Action: runCheck,
+	}
+}
+
+func runCheck(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return invalidInput(fmt.Errorf("no CSV file specified"))
+	}
+	legalityFormat, err := legality.Lookup(cmd.String("legality"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	decklist, err := loadDecklist(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	// Legalities come from Scryfall, so every card is resolved first
+	resolver := resolve.NewResolver()
+	resolver.SetConcurrency(cmd.Int("concurrency"))
+	if _, err := resolver.ResolveData(decklist, nil); err != nil {
+		return err
+	}
+
+	result := legality.Check(decklist, legalityFormat)
+	if cmd.Bool("json") {
+		data, err := sonic.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode legality result: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if err := result.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if !result.Legal() {
+		return invalidInput(fmt.Errorf("deck is not legal in %s: %d violation(s)", legalityFormat.Name, len(result.Violations)))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+)
+
+// Process exit codes
+const (
+	exitSuccess    = 0 // Every card was generated
+	exitFailure    = 1 // Nothing usable was generated
+	exitInputError = 2 // Invalid flags or decklist
+	exitPartial    = 3 // Output was written, but some cards failed
+)
+
+// partialError reports output that was written with some cards missing or
+// printed without art
+type partialError struct {
+	failed  int
+	entries int
+}
+
+func (e *partialError) Error() string {
+	return fmt.Sprintf("%d of %d card(s) had errors", e.failed, e.entries)
+}
+
+// invalidInput tags an error caused by bad flags or decklist contents
+func invalidInput(err error) error {
+	return failure.New(failure.InvalidInput, err)
+}
+
+// exitCode maps the error returned by a command to the process exit code
+func exitCode(err error) int {
+	var partial *partialError
+	switch {
+	case err == nil:
+		return exitSuccess
+	case errors.As(err, &partial):
+		return exitPartial
+	case failure.KindOf(err) == failure.InvalidInput:
+		return exitInputError
+	default:
+		return exitFailure
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	require.Equal(t, exitSuccess, exitCode(nil))
+	require.Equal(t, exitFailure, exitCode(errors.New("failed to write output")))
+	require.Equal(t, exitInputError, exitCode(invalidInput(errors.New("unknown card size"))))
+	require.Equal(t, exitInputError, exitCode(fmt.Errorf("wrapped: %w", invalidInput(errors.New("bad flag")))))
+	require.Equal(t, exitPartial, exitCode(&partialError{failed: 1, entries: 3}))
+}
+
+func TestRunResult(t *testing.T) {
+	ok := model.CardEntry{Qty: 1, ID: "bolt", Faces: []model.Face{{Name: "Lightning Bolt"}}}
+	missing := model.CardEntry{Qty: 1, ID: "missing", Err: errors.New("Error: 404 Not Found")}
+
+	t.Run("every card generated", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{ok}}
+		require.NoError(t, runResult(decklist, model.NewSummary(decklist, "pdf", "deck.pdf")))
+	})
+
+	t.Run("some cards failed", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{ok, missing}}
+		err := runResult(decklist, model.NewSummary(decklist, "pdf", "deck.pdf"))
+		require.EqualError(t, err, "1 of 2 card(s) had errors")
+		require.Equal(t, exitPartial, exitCode(err))
+	})
+
+	t.Run("no card generated", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{missing}}
+		err := runResult(decklist, model.NewSummary(decklist, "pdf", "deck.pdf"))
+		require.Error(t, err)
+		require.Equal(t, exitFailure, exitCode(err))
+	})
+}
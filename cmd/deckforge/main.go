@@ -2,124 +2,403 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/daltonalley/deckforge-cli/internal/deck"
+	"github.com/daltonalley/deckforge-cli/internal/legality"
+	"github.com/daltonalley/deckforge-cli/internal/model"
 	"github.com/daltonalley/deckforge-cli/internal/pdf"
 	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/internal/render"
+	"github.com/daltonalley/deckforge-cli/internal/report"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/daltonalley/deckforge-cli/internal/stats"
+	"github.com/daltonalley/deckforge-cli/scryfall"
 	"github.com/urfave/cli/v3"
 )
 
+// cardResolver fetches card data and images for a decklist
+type cardResolver interface {
+	SetConcurrency(n int)
+	SetLanguages(languages []string)
+	SetImageQuality(quality string)
+	Resolve(decklist *model.Decklist, reporter progress.Reporter) (*resolve.Report, error)
+	ResolveRelated(decklist *model.Decklist, qty int, reporter progress.Reporter) (*resolve.Report, error)
+}
+
+// newResolver creates the resolver for a generation run; replaced in tests
+var newResolver = func() cardResolver {
+	return resolve.NewResolver()
+}
+
 func main() {
-	cmd := &cli.Command{
+	err := newApp().Run(context.Background(), os.Args)
+	var partial *partialError
+	switch {
+	case errors.As(err, &partial):
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(exitCode(err))
+}
+
+// newApp builds the command line interface
+func newApp() *cli.Command {
+	return &cli.Command{
 		Name:  "deckforge",
 		Usage: "Generate printable MTG deck PDFs from Archidekt CSV exports",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "output",
 				Aliases: []string{"o"},
 				Value:   "",
-				Usage:   "Output PDF filename (defaults to CSV name)",
+				Usage:   "Output filename or image directory (defaults to CSV name)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "pdf",
+				Usage: "Output format: " + formatNames(),
+			},
+			&cli.IntFlag{
+				Name:  "dpi",
+				Value: pdf.DefaultDPI,
+				Usage: "Image export resolution in dots per inch",
+			},
+			&cli.StringFlag{
+				Name:  "image-type",
+				Value: pdf.DefaultImageFormat,
+				Usage: "Image export file type: png or jpeg",
 			},
 			&cli.FloatFlag{
 				Name:  "bleed",
 				Value: 3.0,
 				Usage: "Bleed margin in mm around each card",
 			},
+			&cli.StringFlag{
+				Name:  "card-size",
+				Value: pdf.CardSizeStandard.Name,
+				Usage: "Card size profile: standard, poker or japanese (oversized cards are detected automatically)",
+			},
+			&cli.StringFlag{
+				Name:  "page-size",
+				Value: "card",
+				Usage: "Page size: card (one card per page), letter, a4, a3, tabloid or WIDTHxHEIGHT in mm",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: resolve.DefaultConcurrency,
+				Usage: "Number of cards fetched in parallel",
+			},
+			&cli.StringFlag{
+				Name:  "lang",
+				Usage: "Preferred printing languages in order, e.g. ja,de (English is always the last fallback)",
+			},
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write a per-card generation report to this file (.json or .md)",
+			},
+			&cli.StringFlag{
+				Name:  "legality",
+				Usage: "Check the deck is legal in this format before generating (e.g. commander, modern, pauper)",
+			},
+			&cli.StringFlag{
+				Name:  "dfc",
+				Value: string(render.DFCSeparate),
+				Usage: "Double-faced cards: separate (every face as a card), duplex (backs behind fronts) or checklist (front plus a checklist insert)",
+			},
+			&cli.BoolFlag{
+				Name:  "tokens",
+				Usage: "Also print the tokens, emblems, meld parts and combo pieces the deck's cards make",
+			},
+			&cli.IntFlag{
+				Name:  "token-qty",
+				Value: 1,
+				Usage: "Copies of each token printed with --tokens",
+			},
+			&cli.BoolFlag{
+				Name:  "stats-page",
+				Usage: "Append a page of deck statistics to the PDF",
+			},
 			&cli.BoolFlag{
 				Name:    "quiet",
 				Aliases: []string{"q"},
-				Usage:   "Suppress progress output",
+				Usage:   "Suppress text progress output",
 			},
+		}, slices.Concat(progressFlags(), strictFlags(), calibrationFlags())...),
+		Commands: []*cli.Command{
+			calibrateCommand(),
+			validateCommand(),
+			checkCommand(),
+			statsCommand(),
+			priceCommand(),
 		},
 		Action: runDeckForge,
 	}
-
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
 }
 
-func runDeckForge(ctx context.Context, cmd *cli.Command) error {
+func runDeckForge(ctx context.Context, cmd *cli.Command) (err error) {
 	// Get arguments
 	if cmd.Args().Len() == 0 {
-		return fmt.Errorf("no CSV file specified")
+		return invalidInput(fmt.Errorf("no CSV file specified"))
 	}
 
 	csvPath := cmd.Args().Get(0)
 
+	format, err := render.Lookup(cmd.String("format"))
+	if err != nil {
+		return invalidInput(err)
+	}
+
 	// Determine output path
 	outputPath := cmd.String("output")
 	if outputPath == "" {
 		baseName := strings.TrimSuffix(filepath.Base(csvPath), filepath.Ext(filepath.Base(csvPath)))
-		outputPath = baseName + ".pdf"
+		outputPath = baseName + format.Extension
 	}
 
 	// Get configuration
 	bleedAmount := cmd.Float("bleed")
-	quiet := cmd.Bool("quiet")
-
-	// Open and parse CSV
-	file, err := os.Open(csvPath)
+	cardSize, err := pdf.ParseCardSize(cmd.String("card-size"))
 	if err != nil {
-		return fmt.Errorf("failed to open CSV file: %w", err)
+		return invalidInput(err)
+	}
+	pageSize, err := pdf.ParsePageSize(cmd.String("page-size"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	calibration, err := calibrationFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	maxErrors, err := maxErrorsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	languages, err := languagesFromFlag(cmd.String("lang"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	tokenQty := cmd.Int("token-qty")
+	if tokenQty < 1 {
+		return invalidInput(fmt.Errorf("--token-qty must be at least 1, got %d", tokenQty))
+	}
+	var legalityCheck *legality.Format
+	if name := cmd.String("legality"); name != "" {
+		legalityFormat, err := legality.Lookup(name)
+		if err != nil {
+			return invalidInput(err)
+		}
+		legalityCheck = &legalityFormat
+	}
+	reportPath := cmd.String("report")
+	if reportPath != "" {
+		if err := report.CheckPath(reportPath); err != nil {
+			return invalidInput(err)
+		}
 	}
-	defer file.Close()
 
-	decklist, err := deck.ParseDecklistCSV(file)
+	// Create the renderer up front so invalid options fail before any fetching
+	renderer, err := render.New(format.Name, render.Options{
+		Bleed:       bleedAmount,
+		CardSize:    cardSize,
+		PageSize:    pageSize,
+		Calibration: calibration,
+		DPI:         cmd.Int("dpi"),
+		ImageType:   cmd.String("image-type"),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %w", err)
+		return invalidInput(err)
+	}
+	var textPager render.TextPager
+	if cmd.Bool("stats-page") {
+		pager, ok := renderer.(render.TextPager)
+		if !ok {
+			return invalidInput(fmt.Errorf("--stats-page is only supported by PDF formats"))
+		}
+		textPager = pager
+	}
+	dfcMode, err := render.ParseDFCMode(cmd.String("dfc"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	if _, ok := renderer.(render.TextPager); dfcMode == render.DFCChecklist && !ok {
+		return invalidInput(fmt.Errorf("--dfc checklist is only supported by PDF formats"))
 	}
 
-	// Convert to pdf.Decklist type
-	pdfDecklist := &pdf.Decklist{}
-	for _, card := range decklist.Cards {
-		pdfDecklist.Cards = append(pdfDecklist.Cards, pdf.CardEntry{
-			Qty: card.Qty,
-			ID:  card.ID,
-		})
+	// Open and parse CSV
+	decklist, err := loadDecklist(csvPath)
+	if err != nil {
+		return err
 	}
 
 	// Create components
-	pdfGen := pdf.NewGenerator(bleedAmount)
-	pdfGen.SetOutputPath(outputPath)
-	var progressReporter progress.Reporter
-	if !quiet {
-		progressReporter = progress.NewProgressReporter()
+	progressReporter, closeProgress, err := reporterFromFlags(cmd)
+	if err != nil {
+		return err
 	}
+	defer closeProgress()
 
 	// Calculate total operations for progress tracking
 	totalOps := calculateTotalOperations(decklist)
 
-	// Start progress tracking
+	// Start progress tracking. Every started run reports how it finished,
+	// including aborted and failed runs.
+	publishedPath := ""
 	if progressReporter != nil {
-		progressReporter.StartUnified("Starting PDF generation", totalOps)
+		progressReporter.StartUnified(fmt.Sprintf("Starting %s generation", format.Name), totalOps)
+		defer func() {
+			progressReporter.Finish(model.NewSummary(decklist, format.Name, publishedPath), runStatus(err), err)
+		}()
+	}
+
+	// Resolve every card before rendering so renderers only read local data
+	resolver := newResolver()
+	resolver.SetConcurrency(cmd.Int("concurrency"))
+	resolver.SetLanguages(languages)
+	if selector, ok := renderer.(render.ImageQualitySelector); ok {
+		resolver.SetImageQuality(selector.ImageQuality())
+	}
+	if _, err := resolver.Resolve(decklist, progressReporter); err != nil {
+		return err
+	}
+	if cmd.Bool("tokens") {
+		if _, err := resolver.ResolveRelated(decklist, tokenQty, progressReporter); err != nil {
+			return err
+		}
 	}
 
-	// Generate PDF
-	if err := pdfGen.GeneratePDF(pdfDecklist, progressReporter); err != nil {
-		return fmt.Errorf("failed to generate PDF: %w", err)
+	// Refuse to print decks that are not legal in the requested format
+	if legalityCheck != nil {
+		result := legality.Check(decklist, *legalityCheck)
+		if !result.Legal() {
+			if err := result.WriteText(os.Stderr); err != nil {
+				return err
+			}
+			return invalidInput(fmt.Errorf("deck is not legal in %s: %d violation(s)", result.Format, len(result.Violations)))
+		}
 	}
 
-	// Finish progress reporting
-	if progressReporter != nil {
-		progressReporter.Finish(decklist, outputPath)
+	// In strict mode, stop before any output is written
+	if err := checkErrorLimit(decklist, maxErrors); err != nil {
+		if reportPath != "" {
+			summary := model.NewSummary(decklist, format.Name, "")
+			if err := report.New(decklist, summary, time.Now()).Write(reportPath); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	// Render the selected output format
+	render.ArrangeFaces(decklist, dfcMode)
+	if textPager != nil {
+		textPager.SetTextPage(pdf.TextPage{Label: "Statistics", Lines: stats.Compute(decklist).Lines()})
+	}
+	staging := stagingPath(outputPath)
+	renderer.SetOutputPath(staging)
+	renderErr := render.Render(renderer, decklist, progressReporter)
+
+	// Render failures count towards the error limit, so the output is only
+	// published once rendering has finished within it
+	limitErr := checkErrorLimit(decklist, maxErrors)
+	if renderErr == nil && limitErr == nil {
+		if err := publishOutput(staging, outputPath); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+		publishedPath = outputPath
+	} else {
+		os.RemoveAll(staging)
+	}
+	summary := model.NewSummary(decklist, format.Name, publishedPath)
+
+	// The report is written even when rendering fails, to show what went wrong
+	if reportPath != "" {
+		if err := report.New(decklist, summary, time.Now()).Write(reportPath); err != nil {
+			return err
+		}
+	}
+	if renderErr != nil {
+		return fmt.Errorf("failed to generate %s: %w", format.Name, renderErr)
+	}
+	if limitErr != nil {
+		return limitErr
+	}
+	return runResult(decklist, summary)
+}
+
+// loadDecklist parses a decklist CSV file and names the deck after the file
+func loadDecklist(csvPath string) (*model.Decklist, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return nil, invalidInput(fmt.Errorf("failed to open CSV file: %w", err))
 	}
+	defer file.Close()
 
-	return nil
+	decklist, err := deck.ParseDecklistCSV(file)
+	if err != nil {
+		return nil, invalidInput(fmt.Errorf("failed to parse CSV: %w", err))
+	}
+
+	decklist.Name = strings.TrimSuffix(filepath.Base(csvPath), filepath.Ext(csvPath))
+	decklist.Source = csvPath
+	return decklist, nil
+}
+
+// languagesFromFlag parses a comma-separated list of Scryfall language codes
+func languagesFromFlag(value string) ([]string, error) {
+	var languages []string
+	for _, code := range strings.Split(value, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if !scryfall.IsLanguage(code) {
+			return nil, fmt.Errorf("unknown language '%s': use Scryfall codes such as %s", code, strings.Join(scryfall.Languages[:9], ", "))
+		}
+		languages = append(languages, code)
+	}
+	return languages, nil
+}
+
+// runResult turns a finished run into the command's error: nil when every
+// card was generated, a total failure when no card was printed, and a partial
+// success otherwise
+func runResult(decklist *model.Decklist, summary model.Summary) error {
+	if summary.Failed == 0 {
+		return nil
+	}
+	for _, entry := range decklist.Cards {
+		if entry.Resolved() && entry.Err == nil {
+			return &partialError{failed: summary.Failed, entries: summary.Entries}
+		}
+	}
+	return fmt.Errorf("none of the %d card(s) could be generated", summary.Entries)
+}
+
+// formatNames lists the registered output formats for help text
+func formatNames() string {
+	var names []string
+	for _, format := range render.Formats() {
+		names = append(names, format.Name)
+	}
+	return strings.Join(names, ", ")
 }
 
 // calculateTotalOperations calculates the total number of operations for unified progress tracking
-func calculateTotalOperations(decklist *deck.Decklist) int {
+func calculateTotalOperations(decklist *model.Decklist) int {
 	total := 0
 	for range decklist.Cards {
 		total += 1 // Card data fetch
-		total += 1 // Page generation
+		total += 1 // Card rendering
 	}
-	total += 1 // Assembling PDF
+	total += 1 // Writing output
 	return total
 }
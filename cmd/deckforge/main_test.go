@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguagesFromFlag(t *testing.T) {
+	t.Run("comma-separated codes in order", func(t *testing.T) {
+		languages, err := languagesFromFlag(" JA, de,,")
+		require.NoError(t, err)
+		require.Equal(t, []string{"ja", "de"}, languages)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		languages, err := languagesFromFlag("")
+		require.NoError(t, err)
+		require.Empty(t, languages)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		_, err := languagesFromFlag("ja,jp")
+		require.ErrorContains(t, err, "unknown language 'jp'")
+	})
+}
+
+// fakeResolver resolves every entry locally, without network access
+type fakeResolver struct {
+	resolveEntry func(entry *model.CardEntry)
+}
+
+func (r *fakeResolver) SetConcurrency(n int)            {}
+func (r *fakeResolver) SetLanguages(languages []string) {}
+func (r *fakeResolver) SetImageQuality(quality string)  {}
+func (r *fakeResolver) Resolve(decklist *model.Decklist, reporter progress.Reporter) (*resolve.Report, error) {
+	for i := range decklist.Cards {
+		r.resolveEntry(&decklist.Cards[i])
+	}
+	return &resolve.Report{Total: len(decklist.Cards)}, nil
+}
+func (r *fakeResolver) ResolveRelated(decklist *model.Decklist, qty int, reporter progress.Reporter) (*resolve.Report, error) {
+	return &resolve.Report{}, nil
+}
+
+func TestRunDeckForge(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "deck.csv")
+	const counterspell = "1920dae4-fb92-4f19-ae4b-eb3276b8dac7"
+	decklist := "4,Lightning Bolt,a65e485b-03a2-4634-9218-f5bb7c104d41\n1,Counterspell," + counterspell + "\n"
+	require.NoError(t, os.WriteFile(csvPath, []byte(decklist), 0644))
+
+	goodImage := filepath.Join(dir, "good.jpg")
+	out, err := os.Create(goodImage)
+	require.NoError(t, err)
+	require.NoError(t, jpeg.Encode(out, image.NewRGBA(image.Rect(0, 0, 488, 680)), nil))
+	require.NoError(t, out.Close())
+	corruptImage := filepath.Join(dir, "corrupt.jpg")
+	require.NoError(t, os.WriteFile(corruptImage, []byte("not a jpeg"), 0644))
+
+	// run generates the deck with the given image for Counterspell, or a
+	// fetch error when it is empty, and returns the progress events
+	run := func(t *testing.T, counterspellImage string, args ...string) ([]progress.Event, error) {
+		newResolver = func() cardResolver {
+			return &fakeResolver{resolveEntry: func(entry *model.CardEntry) {
+				imagePath := goodImage
+				if entry.ID == counterspell {
+					if counterspellImage == "" {
+						entry.Err = failure.New(failure.NotFound, errors.New("Error: 404 Not Found"))
+						return
+					}
+					imagePath = counterspellImage
+				}
+				entry.Card = scryfall.Card{ID: entry.ID, Name: entry.Name}
+				entry.Faces = []model.Face{{Name: entry.Name, ImagePath: imagePath}}
+			}}
+		}
+		t.Cleanup(func() { newResolver = func() cardResolver { return resolve.NewResolver() } })
+
+		progressPath := filepath.Join(t.TempDir(), "progress.jsonl")
+		args = append([]string{"deckforge", "--progress", "json", "--progress-file", progressPath}, args...)
+		runErr := newApp().Run(context.Background(), append(args, csvPath))
+
+		data, err := os.ReadFile(progressPath)
+		require.NoError(t, err, "run ended before progress started: %v", runErr)
+		var events []progress.Event
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var event progress.Event
+			require.NoError(t, sonic.Unmarshal([]byte(line), &event), line)
+			events = append(events, event)
+		}
+		return events, runErr
+	}
+
+	// finished returns the last event, which must be the only finished event
+	finished := func(t *testing.T, events []progress.Event) progress.Event {
+		count := 0
+		for _, event := range events {
+			if event.Type == progress.EventFinished {
+				count++
+			}
+		}
+		require.Equal(t, 1, count)
+		last := events[len(events)-1]
+		require.Equal(t, progress.EventFinished, last.Type)
+		return last
+	}
+
+	t.Run("success", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		events, err := run(t, goodImage, "-o", outputPath)
+		require.NoError(t, err)
+		event := finished(t, events)
+		require.Equal(t, progress.StatusSuccess, event.Status)
+		require.Equal(t, outputPath, event.Summary.OutputPath)
+		require.FileExists(t, outputPath)
+	})
+
+	t.Run("partial success", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		events, err := run(t, "", "-o", outputPath)
+		require.Equal(t, exitPartial, exitCode(err))
+		event := finished(t, events)
+		require.Equal(t, progress.StatusPartial, event.Status)
+		require.Equal(t, err.Error(), event.Message)
+		require.FileExists(t, outputPath)
+	})
+
+	t.Run("strict abort before rendering", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		events, err := run(t, "", "--strict", "-o", outputPath)
+		require.ErrorContains(t, err, "aborted")
+		event := finished(t, events)
+		require.Equal(t, progress.StatusFailed, event.Status)
+		require.Equal(t, err.Error(), event.Message)
+		require.Empty(t, event.Summary.OutputPath)
+		require.NoFileExists(t, outputPath)
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "missing", "deck.pdf")
+		events, err := run(t, goodImage, "-o", outputPath)
+		require.ErrorContains(t, err, "failed to generate pdf")
+		event := finished(t, events)
+		require.Equal(t, progress.StatusFailed, event.Status)
+		require.Equal(t, err.Error(), event.Message)
+	})
+
+	t.Run("error limit exceeded while rendering", func(t *testing.T) {
+		outputDir := t.TempDir()
+		outputPath := filepath.Join(outputDir, "deck.pdf")
+		events, err := run(t, corruptImage, "--max-errors", "0", "-o", outputPath)
+		require.ErrorContains(t, err, "aborted: 1 card(s) had errors")
+		event := finished(t, events)
+		require.Equal(t, progress.StatusFailed, event.Status)
+		require.Empty(t, event.Summary.OutputPath)
+
+		// Nothing is published, and the staged output is removed
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+func TestCheckCommand(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "deck.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("4,Lightning Bolt,a65e485b-03a2-4634-9218-f5bb7c104d41\n"), 0644))
+
+	t.Run("the format is chosen with --legality", func(t *testing.T) {
+		err := newApp().Run(context.Background(), []string{"deckforge", "check", "--legality", "nonexistent", csvPath})
+		require.Equal(t, exitInputError, exitCode(err))
+		require.ErrorContains(t, err, "unknown format 'nonexistent'")
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stagingPath returns a hidden path next to outputPath that output is
+// rendered to before it is published
+func stagingPath(outputPath string) string {
+	name := fmt.Sprintf(".%s.%d.tmp", filepath.Base(outputPath), os.Getpid())
+	return filepath.Join(filepath.Dir(outputPath), name)
+}
+
+// publishOutput moves rendered output from the staging path to outputPath.
+// Files replace any existing file; directories are merged file by file so
+// other files in an existing output directory are kept.
+func publishOutput(staging, outputPath string) error {
+	info, err := os.Stat(staging)
+	if err != nil {
+		return fmt.Errorf("failed to publish output: %w", err)
+	}
+	if !info.IsDir() {
+		if err := os.Rename(staging, outputPath); err != nil {
+			return fmt.Errorf("failed to publish output: %w", err)
+		}
+		return nil
+	}
+
+	err = filepath.WalkDir(staging, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(staging, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(outputPath, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return os.Rename(path, target)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish output: %w", err)
+	}
+	return os.RemoveAll(staging)
+}
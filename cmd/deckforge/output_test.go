@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishOutput(t *testing.T) {
+	t.Run("replaces an existing file", func(t *testing.T) {
+		dir := t.TempDir()
+		outputPath := filepath.Join(dir, "deck.pdf")
+		require.NoError(t, os.WriteFile(outputPath, []byte("old"), 0644))
+		staging := stagingPath(outputPath)
+		require.Equal(t, dir, filepath.Dir(staging))
+		require.NoError(t, os.WriteFile(staging, []byte("new"), 0644))
+
+		require.NoError(t, publishOutput(staging, outputPath))
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Equal(t, "new", string(data))
+		require.NoFileExists(t, staging)
+	})
+
+	t.Run("merges a directory into an existing one", func(t *testing.T) {
+		dir := t.TempDir()
+		outputPath := filepath.Join(dir, "deck")
+		require.NoError(t, os.MkdirAll(filepath.Join(outputPath, "front"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(outputPath, "notes.txt"), []byte("keep"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(outputPath, "front", "001_Bolt.png"), []byte("old"), 0644))
+
+		staging := stagingPath(outputPath)
+		require.NoError(t, os.MkdirAll(filepath.Join(staging, "front"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(staging, "front", "001_Bolt.png"), []byte("new"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(staging, "manifest.json"), []byte("{}"), 0644))
+
+		require.NoError(t, publishOutput(staging, outputPath))
+		data, err := os.ReadFile(filepath.Join(outputPath, "front", "001_Bolt.png"))
+		require.NoError(t, err)
+		require.Equal(t, "new", string(data))
+		require.FileExists(t, filepath.Join(outputPath, "manifest.json"))
+		require.FileExists(t, filepath.Join(outputPath, "notes.txt"))
+		require.NoDirExists(t, staging)
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/price"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/urfave/cli/v3"
+)
+
+// priceCommand estimates what the deck would cost to buy
+func priceCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "price",
+		Usage:     "Estimate the deck's price from Scryfall, per card and per section",
+		ArgsUsage: "<decklist.csv>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "currency",
+				Value: string(price.USD),
+				Usage: "Price currency: usd, eur or tix",
+			},
+			&cli.BoolFlag{
+				Name:  "cheapest",
+				Usage: "Price each card at its cheapest printing instead of the listed one",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: resolve.DefaultConcurrency,
+				Usage: "Number of cards fetched in parallel",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the price report as JSON",
+			},
+		},
+		Action: runPrice,
+	}
+}
+
+func runPrice(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return invalidInput(fmt.Errorf("no CSV file specified"))
+	}
+	currency, err := price.ParseCurrency(cmd.String("currency"))
+	if err != nil {
+		return invalidInput(err)
+	}
+	decklist, err := loadDecklist(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	resolver := resolve.NewResolver()
+	resolver.SetConcurrency(cmd.Int("concurrency"))
+	if _, err := resolver.ResolveData(decklist, nil); err != nil {
+		return err
+	}
+	var printings map[string][]scryfall.Card
+	if cmd.Bool("cheapest") {
+		// Cards whose printings can't be fetched fall back to the listed printing
+		printings, _ = resolver.ResolvePrintings(decklist)
+	}
+
+	report := price.Estimate(decklist, currency, printings)
+	if cmd.Bool("json") {
+		data, err := sonic.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode price report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	return report.WriteText(os.Stdout)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/urfave/cli/v3"
+)
+
+// progressFlags selects how generation progress is reported
+func progressFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "progress",
+			Value: "text",
+			Usage: "Progress output: text (a live bar on terminals), or json for newline-delimited JSON events on stderr",
+		},
+		&cli.StringFlag{
+			Name:  "progress-file",
+			Usage: "Write JSON progress events to this file instead of stderr",
+		},
+	}
+}
+
+// runStatus maps the error a run ended with to its progress status
+func runStatus(err error) progress.Status {
+	switch exitCode(err) {
+	case exitSuccess:
+		return progress.StatusSuccess
+	case exitPartial:
+		return progress.StatusPartial
+	default:
+		return progress.StatusFailed
+	}
+}
+
+// reporterFromFlags creates the progress reporter selected on the command line.
+// The returned reporter is nil when progress output is disabled; close releases
+// any progress file.
+func reporterFromFlags(cmd *cli.Command) (progress.Reporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch cmd.String("progress") {
+	case "text":
+		if cmd.String("progress-file") != "" {
+			return nil, noop, invalidInput(fmt.Errorf("--progress-file requires --progress=json"))
+		}
+		if cmd.Bool("quiet") {
+			return nil, noop, nil
+		}
+		return progress.NewTextReporter(), noop, nil
+	case "json":
+		var out io.Writer = os.Stderr
+		closer := noop
+		if path := cmd.String("progress-file"); path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				return nil, noop, fmt.Errorf("failed to create progress file: %w", err)
+			}
+			out, closer = file, file.Close
+		}
+		return progress.NewJSONReporter(out), closer, nil
+	default:
+		return nil, noop, invalidInput(fmt.Errorf("unknown progress output '%s'", cmd.String("progress")))
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/daltonalley/deckforge-cli/internal/stats"
+	"github.com/urfave/cli/v3"
+)
+
+// statsCommand prints deck statistics without generating any output
+func statsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "stats",
+		Usage:     "Show the mana curve, colored pips, card types and produced mana of a decklist",
+		ArgsUsage: "<decklist.csv>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: resolve.DefaultConcurrency,
+				Usage: "Number of cards fetched in parallel",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the statistics as JSON",
+			},
+		},
+		Action: runStats,
+	}
+}
+
+func runStats(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return invalidInput(fmt.Errorf("no CSV file specified"))
+	}
+	decklist, err := loadDecklist(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	resolver := resolve.NewResolver()
+	resolver.SetConcurrency(cmd.Int("concurrency"))
+	if _, err := resolver.ResolveData(decklist, nil); err != nil {
+		return err
+	}
+
+	deckStats := stats.Compute(decklist)
+	if cmd.Bool("json") {
+		data, err := sonic.MarshalIndent(deckStats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode statistics: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	return deckStats.WriteText(os.Stdout)
+}
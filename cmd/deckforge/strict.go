@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/urfave/cli/v3"
+)
+
+// noErrorLimit lets a run finish however many cards fail
+const noErrorLimit = -1
+
+// maxListedErrors caps how many failed cards are named in an abort message
+const maxListedErrors = 5
+
+// strictFlags control whether a run with failed cards is aborted
+func strictFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Abort without writing output if any card fails to resolve or is missing an image",
+		},
+		&cli.IntFlag{
+			Name:  "max-errors",
+			Usage: "Abort without writing output if more than this many cards have errors",
+		},
+	}
+}
+
+// maxErrorsFromFlags returns how many cards may fail before a run is
+// aborted, or noErrorLimit
+func maxErrorsFromFlags(cmd *cli.Command) (int, error) {
+	maxErrors := cmd.Int("max-errors")
+	switch {
+	case cmd.IsSet("max-errors") && maxErrors < 0:
+		return 0, invalidInput(fmt.Errorf("--max-errors must be zero or more"))
+	case cmd.Bool("strict"):
+		if cmd.IsSet("max-errors") && maxErrors != 0 {
+			return 0, invalidInput(fmt.Errorf("--strict cannot be combined with --max-errors %d", maxErrors))
+		}
+		return 0, nil
+	case cmd.IsSet("max-errors"):
+		return maxErrors, nil
+	default:
+		return noErrorLimit, nil
+	}
+}
+
+// checkErrorLimit fails when more decklist entries have errors than maxErrors allows
+func checkErrorLimit(decklist *model.Decklist, maxErrors int) error {
+	if maxErrors == noErrorLimit {
+		return nil
+	}
+
+	var failed []string
+	for _, entry := range decklist.Cards {
+		if entry.HasErrors() {
+			failed = append(failed, entry.DisplayName())
+		}
+	}
+	if len(failed) <= maxErrors {
+		return nil
+	}
+
+	names := strings.Join(failed, ", ")
+	if len(failed) > maxListedErrors {
+		names = fmt.Sprintf("%s and %d more", strings.Join(failed[:maxListedErrors], ", "), len(failed)-maxListedErrors)
+	}
+	if maxErrors == 0 {
+		return fmt.Errorf("aborted: %d card(s) had errors and none are allowed: %s", len(failed), names)
+	}
+	return fmt.Errorf("aborted: %d card(s) had errors, more than the %d allowed: %s", len(failed), maxErrors, names)
+}
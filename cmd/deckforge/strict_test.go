@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckErrorLimit(t *testing.T) {
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Qty: 4, ID: "bolt", Faces: []model.Face{{Name: "Lightning Bolt"}}},
+		{Qty: 1, ID: "missing", Name: "Missing Card", Err: errors.New("Error: 404 Not Found")},
+		{Qty: 1, ID: "blank", Card: scryfall.Card{Name: "Blank Card"}, Faces: []model.Face{{Name: "Blank Card", Err: errors.New("no image")}}},
+	}}
+
+	t.Run("no limit", func(t *testing.T) {
+		require.NoError(t, checkErrorLimit(decklist, noErrorLimit))
+	})
+
+	t.Run("strict mode allows no errors", func(t *testing.T) {
+		err := checkErrorLimit(decklist, 0)
+		require.EqualError(t, err, "aborted: 2 card(s) had errors and none are allowed: Missing Card, Blank Card")
+		require.Equal(t, exitFailure, exitCode(err))
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		require.NoError(t, checkErrorLimit(decklist, 2))
+		require.EqualError(t, checkErrorLimit(decklist, 1),
+			"aborted: 2 card(s) had errors, more than the 1 allowed: Missing Card, Blank Card")
+	})
+
+	t.Run("long lists are truncated", func(t *testing.T) {
+		many := &model.Decklist{}
+		for range 7 {
+			many.Cards = append(many.Cards, model.CardEntry{Qty: 1, ID: "x"})
+		}
+		require.EqualError(t, checkErrorLimit(many, 0),
+			"aborted: 7 card(s) had errors and none are allowed: x, x, x, x, x and 2 more")
+	})
+}
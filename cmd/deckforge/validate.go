@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/deck"
+	"github.com/daltonalley/deckforge-cli/internal/resolve"
+	"github.com/daltonalley/deckforge-cli/internal/validate"
+	"github.com/urfave/cli/v3"
+)
+
+// validateCommand checks a decklist without generating any output
+func validateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Check a decklist for invalid rows, unknown cards and image problems without generating a PDF",
+		ArgsUsage: "<decklist.csv>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: resolve.DefaultConcurrency,
+				Usage: "Number of cards fetched in parallel",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the result as JSON",
+			},
+		},
+		Action: runValidate,
+	}
+}
+
+func runValidate(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() == 0 {
+		return invalidInput(fmt.Errorf("no CSV file specified"))
+	}
+	csvPath := cmd.Args().Get(0)
+
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return invalidInput(fmt.Errorf("failed to open CSV file: %w", err))
+	}
+	defer file.Close()
+
+	// Keep going past invalid rows so every problem is reported at once
+	decklist, rowErrors, err := deck.ParseDecklistCSVLenient(file)
+	if err != nil {
+		return invalidInput(fmt.Errorf("failed to parse CSV: %w", err))
+	}
+	decklist.Name = strings.TrimSuffix(filepath.Base(csvPath), filepath.Ext(csvPath))
+	decklist.Source = csvPath
+
+	resolver := resolve.NewResolver()
+	resolver.SetConcurrency(cmd.Int("concurrency"))
+	if _, err := resolver.ResolveData(decklist, nil); err != nil {
+		return err
+	}
+
+	result := validate.Check(decklist, rowErrors)
+	if cmd.Bool("json") {
+		data, err := sonic.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode validation result: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if err := result.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if !result.Valid() {
+		return invalidInput(fmt.Errorf("decklist has %d error(s)", len(result.Errors)))
+	}
+	return nil
+}
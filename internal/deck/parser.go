@@ -2,76 +2,164 @@ package deck
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
 	"strconv"
+	"strings"
 
+	"github.com/daltonalley/deckforge-cli/internal/model"
 	"github.com/daltonalley/deckforge-cli/scryfall"
 )
 
-// CardEntry represents a single card in the decklist
-type CardEntry struct {
-	Qty  int
-	ID   string
-	Card scryfall.Card // Card data from Scryfall API
+// scryfallIDRegex matches the UUID form of a Scryfall card ID
+var scryfallIDRegex = regexp.MustCompile(`^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$`)
+
+// RowError is an invalid decklist row skipped by ParseDecklistCSVLenient
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Error returns the underlying message, which already names the line
+func (e RowError) Error() string {
+	return e.Err.Error()
 }
 
-// Decklist represents a parsed decklist from CSV
-type Decklist struct {
-	Cards []CardEntry
+// Unwrap returns the underlying error
+func (e RowError) Unwrap() error {
+	return e.Err
 }
 
 // ParseDecklistCSV parses and validates a CSV reader containing decklist data
-// Expected format: quantity,"card name",scryfall_id
-func ParseDecklistCSV(reader io.Reader) (*Decklist, error) {
+// Expected format: quantity,"card name",scryfall_id[,section[,finish[,language]]]
+func ParseDecklistCSV(reader io.Reader) (*model.Decklist, error) {
 	csvReader := csv.NewReader(reader)
+	// Optional columns may be filled on some rows only; parseRecord checks
+	// each row's width
+	csvReader.FieldsPerRecord = -1
 	records, err := csvReader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
 
-	var decklist Decklist
-	scryfallIDRegex := regexp.MustCompile(`^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$`)
-
+	var decklist model.Decklist
 	for i, record := range records {
-		if len(record) < 3 {
-			return nil, fmt.Errorf("invalid CSV format at line %d: expected 3 fields, got %d", i+1, len(record))
+		entry, err := parseRecord(record, i+1)
+		if err != nil {
+			return nil, err
 		}
+		decklist.Cards = append(decklist.Cards, entry)
+	}
 
-		// Parse quantity
-		qty, err := strconv.Atoi(record[0])
-		if err != nil {
-			return nil, fmt.Errorf("invalid quantity '%s' at line %d: %w", record[0], i+1, err)
+	return &decklist, nil
+}
+
+// ParseDecklistCSVLenient parses a decklist like ParseDecklistCSV, but skips
+// invalid rows and returns them alongside the valid entries instead of
+// stopping at the first one
+func ParseDecklistCSVLenient(reader io.Reader) (*model.Decklist, []RowError, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	var decklist model.Decklist
+	var rowErrors []RowError
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
 		}
-		if qty <= 0 {
-			return nil, fmt.Errorf("quantity must be positive at line %d, got %d", i+1, qty)
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: fmt.Errorf("failed to read CSV: %w", err)})
+			continue
 		}
-
-		// Validate Scryfall ID format
-		scryfallID := record[2]
-		if !scryfallIDRegex.MatchString(scryfallID) {
-			return nil, fmt.Errorf("invalid Scryfall ID format '%s' at line %d", scryfallID, i+1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
 		}
 
-		// Create card entry
-		entry := CardEntry{
-			Qty: qty,
-			ID:  scryfallID,
+		entry, err := parseRecord(record, line)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: err})
+			continue
 		}
 		decklist.Cards = append(decklist.Cards, entry)
 	}
 
-	return &decklist, nil
+	return &decklist, rowErrors, nil
+}
+
+// parseRecord validates a single CSV record into a card entry
+func parseRecord(record []string, line int) (model.CardEntry, error) {
+	if len(record) < 3 {
+		return model.CardEntry{}, fmt.Errorf("invalid CSV format at line %d: expected 3 fields, got %d", line, len(record))
+	}
+
+	// Parse quantity
+	qty, err := strconv.Atoi(record[0])
+	if err != nil {
+		return model.CardEntry{}, fmt.Errorf("invalid quantity '%s' at line %d: %w", record[0], line, err)
+	}
+	if qty <= 0 {
+		return model.CardEntry{}, fmt.Errorf("quantity must be positive at line %d, got %d", line, qty)
+	}
+
+	// Validate Scryfall ID format
+	scryfallID := record[2]
+	if !scryfallIDRegex.MatchString(scryfallID) {
+		return model.CardEntry{}, fmt.Errorf("invalid Scryfall ID format '%s' at line %d", scryfallID, line)
+	}
+
+	// Create card entry
+	entry := model.CardEntry{
+		Line: line,
+		Qty:  qty,
+		Name: strings.TrimSpace(record[1]),
+		ID:   scryfallID,
+	}
+	if len(record) > 3 {
+		entry.Section = strings.TrimSpace(record[3])
+	}
+	if len(record) > 4 {
+		finish, err := parseFinish(record[4])
+		if err != nil {
+			return model.CardEntry{}, fmt.Errorf("%w at line %d", err, line)
+		}
+		entry.Finish = finish
+	}
+	if len(record) > 5 {
+		lang := strings.ToLower(strings.TrimSpace(record[5]))
+		if lang != "" && !scryfall.IsLanguage(lang) {
+			return model.CardEntry{}, fmt.Errorf("invalid language '%s' at line %d", record[5], line)
+		}
+		entry.Lang = lang
+	}
+	return entry, nil
+}
+
+// parseFinish validates an optional finish column
+func parseFinish(value string) (string, error) {
+	finish := strings.ToLower(strings.TrimSpace(value))
+	switch finish {
+	case "", model.FinishNonfoil, model.FinishFoil, model.FinishEtched:
+		return finish, nil
+	default:
+		return "", fmt.Errorf("invalid finish '%s'", value)
+	}
 }
 
-// CalculateTotalPages calculates the total number of pages that will be generated
-func CalculateTotalPages(decklist *Decklist) int {
+// CalculateTotalPages calculates the total number of pages that will be generated.
+// Double-faced cards are only counted correctly once the decklist is resolved.
+func CalculateTotalPages(decklist *model.Decklist) int {
 	total := 0
 	for _, card := range decklist.Cards {
-		if len(card.Card.CardFaces) > 0 {
+		switch {
+		case card.Resolved():
+			total += len(card.Faces) * card.Qty
+		case card.Card.HasFaceImages():
 			total += len(card.Card.CardFaces) * card.Qty
-		} else {
+		default:
 			total += card.Qty
 		}
 	}
@@ -1,9 +1,12 @@
 package deck
 
 import (
+	"io"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/daltonalley/deckforge-cli/internal/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,6 +34,88 @@ func TestParseDecklistCSV(t *testing.T) {
 		require.Equal(t, 2, decklist.Cards[1].Qty)
 	})
 
+	t.Run("valid CSV with optional section", func(t *testing.T) {
+		csvData := `1,"Atraxa, Praetors' Voice",a65e485b-03a2-4634-9218-f5bb7c104d41,Commander
+1,"Sol Ring",b6a5b3b0-2b4b-4c4b-8b2b-2b2b2b2b2b2b,`
+		reader := strings.NewReader(csvData)
+
+		decklist, err := ParseDecklistCSV(reader)
+		require.NoError(t, err)
+		require.Len(t, decklist.Cards, 2)
+		require.Equal(t, "Commander", decklist.Cards[0].Section)
+		require.Equal(t, "", decklist.Cards[1].Section)
+	})
+
+	t.Run("valid CSV with optional finish", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41,Mainboard,Foil
+1,"Sol Ring",b6a5b3b0-2b4b-4c4b-8b2b-2b2b2b2b2b2b,,`
+		reader := strings.NewReader(csvData)
+
+		decklist, err := ParseDecklistCSV(reader)
+		require.NoError(t, err)
+		require.Equal(t, "Lightning Bolt", decklist.Cards[0].Name)
+		require.Equal(t, model.FinishFoil, decklist.Cards[0].Finish)
+		require.Equal(t, "", decklist.Cards[1].Finish)
+	})
+
+	t.Run("invalid CSV - unknown finish", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41,,shiny`
+		reader := strings.NewReader(csvData)
+
+		_, err := ParseDecklistCSV(reader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid finish 'shiny' at line 1")
+	})
+
+	t.Run("valid CSV with optional language", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41,Mainboard,,JA
+1,"Sol Ring",b6a5b3b0-2b4b-4c4b-8b2b-2b2b2b2b2b2b,,,`
+		reader := strings.NewReader(csvData)
+
+		decklist, err := ParseDecklistCSV(reader)
+		require.NoError(t, err)
+		require.Equal(t, "ja", decklist.Cards[0].Lang)
+		require.Equal(t, "", decklist.Cards[1].Lang)
+	})
+
+	t.Run("invalid CSV - unknown language", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41,,,klingon`
+		reader := strings.NewReader(csvData)
+
+		_, err := ParseDecklistCSV(reader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid language 'klingon' at line 1")
+	})
+
+	t.Run("rows with different numbers of optional columns", func(t *testing.T) {
+		file, err := os.Open("testdata/mixed_width.csv")
+		require.NoError(t, err)
+		defer file.Close()
+
+		decklist, err := ParseDecklistCSV(file)
+		require.NoError(t, err)
+		require.Len(t, decklist.Cards, 4)
+		require.Equal(t, "Commander", decklist.Cards[0].Section)
+		require.Equal(t, "", decklist.Cards[1].Section)
+		require.Equal(t, model.FinishFoil, decklist.Cards[2].Finish)
+		require.Equal(t, "ja", decklist.Cards[3].Lang)
+
+		// The lenient parser used by validate accepts the same file
+		_, err = file.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		lenient, rowErrors, err := ParseDecklistCSVLenient(file)
+		require.NoError(t, err)
+		require.Empty(t, rowErrors)
+		require.Equal(t, decklist.Cards, lenient.Cards)
+	})
+
+	t.Run("short row in a mixed-width file", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41,Mainboard
+1,"Sol Ring"`
+		_, err := ParseDecklistCSV(strings.NewReader(csvData))
+		require.EqualError(t, err, "invalid CSV format at line 2: expected 3 fields, got 2")
+	})
+
 	t.Run("invalid CSV - missing quantity field", func(t *testing.T) {
 		csvData := `"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41`
 		reader := strings.NewReader(csvData)
@@ -70,8 +155,8 @@ func TestParseDecklistCSV(t *testing.T) {
 
 func TestCalculateTotalPages(t *testing.T) {
 	t.Run("single card with quantity 1", func(t *testing.T) {
-		decklist := &Decklist{
-			Cards: []CardEntry{
+		decklist := &model.Decklist{
+			Cards: []model.CardEntry{
 				{Qty: 1, ID: "test-id"},
 			},
 		}
@@ -81,8 +166,8 @@ func TestCalculateTotalPages(t *testing.T) {
 	})
 
 	t.Run("multiple cards with different quantities", func(t *testing.T) {
-		decklist := &Decklist{
-			Cards: []CardEntry{
+		decklist := &model.Decklist{
+			Cards: []model.CardEntry{
 				{Qty: 1, ID: "card1"},
 				{Qty: 3, ID: "card2"},
 				{Qty: 2, ID: "card3"},
@@ -94,11 +179,50 @@ func TestCalculateTotalPages(t *testing.T) {
 	})
 
 	t.Run("empty decklist", func(t *testing.T) {
-		decklist := &Decklist{
-			Cards: []CardEntry{},
+		decklist := &model.Decklist{
+			Cards: []model.CardEntry{},
 		}
 
 		pages := CalculateTotalPages(decklist)
 		require.Equal(t, 0, pages)
 	})
 }
+
+func TestParseDecklistCSVLenient(t *testing.T) {
+	t.Run("invalid rows are skipped and reported", func(t *testing.T) {
+		csvData := `1,"Lightning Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41
+0,"Zero",a65e485b-03a2-4634-9218-f5bb7c104d41
+"Missing Quantity",a65e485b-03a2-4634-9218-f5bb7c104d41
+2,"Sol Ring",b6a5b3b0-2b4b-4c4b-8b2b-2b2b2b2b2b2b,Commander,foil
+1,"Bad ID",invalid-id`
+		reader := strings.NewReader(csvData)
+
+		decklist, rowErrors, err := ParseDecklistCSVLenient(reader)
+		require.NoError(t, err)
+		require.Len(t, decklist.Cards, 2)
+		require.Equal(t, 1, decklist.Cards[0].Line)
+		require.Equal(t, 4, decklist.Cards[1].Line)
+		require.Equal(t, model.FinishFoil, decklist.Cards[1].Finish)
+
+		require.Len(t, rowErrors, 3)
+		var lines []int
+		for _, rowErr := range rowErrors {
+			lines = append(lines, rowErr.Line)
+		}
+		require.Equal(t, []int{2, 3, 5}, lines)
+		require.Contains(t, rowErrors[0].Error(), "quantity must be positive at line 2")
+		require.Contains(t, rowErrors[2].Error(), "invalid Scryfall ID format")
+	})
+
+	t.Run("malformed CSV rows", func(t *testing.T) {
+		csvData := `1,"Lightning "Bolt",a65e485b-03a2-4634-9218-f5bb7c104d41
+1,"Sol Ring",b6a5b3b0-2b4b-4c4b-8b2b-2b2b2b2b2b2b`
+		reader := strings.NewReader(csvData)
+
+		decklist, rowErrors, err := ParseDecklistCSVLenient(reader)
+		require.NoError(t, err)
+		require.Len(t, decklist.Cards, 1)
+		require.Len(t, rowErrors, 1)
+		require.Contains(t, rowErrors[0].Error(), "failed to read CSV")
+	})
+}
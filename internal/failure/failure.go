@@ -0,0 +1,77 @@
+// Package failure classifies errors from every stage of generation so they
+// can be reported by category and mapped to exit codes.
+package failure
+
+import (
+	"errors"
+	"image"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Kind is the category of a failure
+type Kind string
+
+// Failure kinds
+const (
+	NotFound     Kind = "not_found"     // Card does not exist on Scryfall
+	RateLimited  Kind = "rate_limited"  // Scryfall asked us to slow down
+	Network      Kind = "network"       // Request failed before a response
+	InvalidInput Kind = "invalid_input" // Bad decklist, flag or request
+	ImageDecode  Kind = "image_decode"  // Image could not be read
+	Render       Kind = "render"        // Output could not be written
+	Unknown      Kind = "unknown"
+)
+
+// Error is an error tagged with its kind
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+// Error returns the underlying message
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New tags err with a kind. Nil errors stay nil, and errors that already have
+// a kind keep it.
+func New(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	var tagged *Error
+	if errors.As(err, &tagged) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf classifies an error: explicitly tagged errors first, then Scryfall
+// and image decoding errors
+func KindOf(err error) Kind {
+	var tagged *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &tagged):
+		return tagged.Kind
+	case errors.Is(err, scryfall.ErrNotFound):
+		return NotFound
+	case errors.Is(err, scryfall.ErrRateLimited):
+		return RateLimited
+	case errors.Is(err, scryfall.ErrNetwork):
+		return Network
+	case errors.Is(err, scryfall.ErrInvalidInput):
+		return InvalidInput
+	case errors.Is(err, image.ErrFormat):
+		return ImageDecode
+	default:
+		return Unknown
+	}
+}
@@ -0,0 +1,46 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind Kind
+	}{
+		{"nil", nil, ""},
+		{"tagged", New(Render, errors.New("no pages generated")), Render},
+		{"wrapped tag", fmt.Errorf("failed: %w", New(InvalidInput, errors.New("bad row"))), InvalidInput},
+		{"scryfall not found", &scryfall.APIError{Status: 404}, NotFound},
+		{"scryfall rate limit", fmt.Errorf("failed to download image: %w", &scryfall.APIError{Status: 429}), RateLimited},
+		{"scryfall bad request", &scryfall.APIError{Status: 400}, InvalidInput},
+		{"network", fmt.Errorf("%w: %w", scryfall.ErrNetwork, errors.New("dial tcp: timeout")), Network},
+		{"image decode", fmt.Errorf("failed to decode image: %w", image.ErrFormat), ImageDecode},
+		{"other", errors.New("boom"), Unknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.kind, KindOf(test.err))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("nil stays nil", func(t *testing.T) {
+		require.NoError(t, New(Render, nil))
+	})
+
+	t.Run("existing kind is kept", func(t *testing.T) {
+		err := New(Render, New(ImageDecode, errors.New("bad png")))
+		require.Equal(t, ImageDecode, KindOf(err))
+		require.EqualError(t, err, "bad png")
+	})
+}
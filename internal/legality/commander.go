@@ -0,0 +1,233 @@
+package legality
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Commander violation kinds
+const (
+	KindCommander     = "commander"      // Missing, ineligible or mismatched commanders
+	KindColorIdentity = "color_identity" // Card outside the commanders' color identity
+	KindCompanion     = "companion"      // Companion section card without Companion
+)
+
+// CommandRule is who may lead a deck in a Commander-style format
+type CommandRule string
+
+// Command rules
+const (
+	CommandLegendary   CommandRule = "legendary"   // Legendary creatures: Commander, Duel Commander, PreDH
+	CommandBrawl       CommandRule = "brawl"       // Legendary creatures and planeswalkers: Brawl, Standard Brawl
+	CommandCreature    CommandRule = "creature"    // Any creature: Pauper Commander (printed rarity is not checked)
+	CommandOathbreaker CommandRule = "oathbreaker" // A planeswalker and its signature spell: Oathbreaker
+)
+
+// commanderTypes describes the cards each rule lets lead a deck
+var commanderTypes = map[CommandRule]string{
+	CommandLegendary:   "a legendary creature",
+	CommandBrawl:       "a legendary creature or planeswalker",
+	CommandCreature:    "a creature",
+	CommandOathbreaker: "a planeswalker",
+}
+
+// maxCommanders is the number of commanders a pairing ability allows
+const maxCommanders = 2
+
+// colorOrder is the conventional WUBRG order for printing color identities
+var colorOrder = []string{"W", "U", "B", "R", "G"}
+
+// partnerWithRegex matches "Partner with <name>" and captures the partner's name
+var partnerWithRegex = regexp.MustCompile(`(?m)^Partner with ([^(\n]+?)\s*(?:\(|$)`)
+
+// checkCommander applies the Commander rules that legality strings don't
+// cover: commander eligibility and pairings, color identity and companions.
+// Singleton and deck size rules are checked with the other formats. In
+// Oathbreaker, instants and sorceries in the Commander section are signature
+// spells, one per oathbreaker.
+func (r *Result) checkCommander(decklist *model.Decklist, rule CommandRule) {
+	var commanders, spells []model.CardEntry
+	for _, entry := range decklist.Cards {
+		if !entry.Resolved() || !strings.EqualFold(entry.SectionName(), "commander") {
+			continue
+		}
+		if rule == CommandOathbreaker && isSpell(entry.Card) {
+			spells = append(spells, entry)
+		} else {
+			commanders = append(commanders, entry)
+		}
+	}
+
+	switch {
+	case len(commanders) == 0:
+		r.Violations = append(r.Violations, Violation{Kind: KindCommander, Message: "deck has no commander: put it in a Commander section"})
+		return
+	case len(commanders) > maxCommanders:
+		r.Violations = append(r.Violations, Violation{Kind: KindCommander,
+			Message: fmt.Sprintf("deck has %d commanders, at most %d allowed", len(commanders), maxCommanders)})
+	case len(commanders) == maxCommanders:
+		if !canPair(commanders[0].Card, commanders[1].Card) {
+			r.add(commanders[1], KindCommander, fmt.Sprintf("cannot share command with %s", commanders[0].Card.Name))
+		}
+	}
+	if rule == CommandOathbreaker && len(spells) != len(commanders) {
+		r.Violations = append(r.Violations, Violation{Kind: KindCommander,
+			Message: fmt.Sprintf("deck has %d signature spell(s) for %d oathbreaker(s): put one instant or sorcery per oathbreaker in the Commander section",
+				len(spells), len(commanders))})
+	}
+
+	identity := map[string]bool{}
+	for i, commander := range commanders {
+		if !canBeCommander(commander.Card, pairedCards(commanders, i), rule) {
+			r.add(commander, KindCommander, fmt.Sprintf("not %s and cannot be your commander", commanderTypes[rule]))
+		}
+		for _, color := range commander.Card.ColorIdentity {
+			identity[color] = true
+		}
+	}
+
+	for _, entry := range decklist.Cards {
+		if !entry.Resolved() || entry.Board() == model.BoardNone {
+			continue
+		}
+		if strings.EqualFold(entry.SectionName(), "companion") && !slices.Contains(entry.Card.Keywords, "Companion") {
+			r.add(entry, KindCompanion, "does not have Companion")
+		}
+		var outside []string
+		for _, color := range entry.Card.ColorIdentity {
+			if !identity[color] {
+				outside = append(outside, color)
+			}
+		}
+		if len(outside) > 0 {
+			r.add(entry, KindColorIdentity, fmt.Sprintf("color identity %s is outside the commander's %s",
+				formatColors(entry.Card.ColorIdentity), formatColors(mapKeys(identity))))
+		}
+	}
+}
+
+// pairedCards returns the other commanders' cards
+func pairedCards(commanders []model.CardEntry, skip int) []scryfall.Card {
+	var cards []scryfall.Card
+	for i, commander := range commanders {
+		if i != skip {
+			cards = append(cards, commander.Card)
+		}
+	}
+	return cards
+}
+
+// canBeCommander reports whether a card may lead a deck under a command
+// rule, alongside its partners
+func canBeCommander(card scryfall.Card, partners []scryfall.Card, rule CommandRule) bool {
+	typeLine := frontTypeLine(card)
+	legendary := strings.Contains(typeLine, "Legendary")
+	creature := strings.Contains(typeLine, "Creature")
+	planeswalker := strings.Contains(typeLine, "Planeswalker")
+	switch rule {
+	case CommandCreature:
+		return creature
+	case CommandOathbreaker:
+		return planeswalker
+	case CommandBrawl:
+		if legendary && planeswalker {
+			return true
+		}
+	}
+	if legendary && creature {
+		return true
+	}
+	if strings.Contains(oracleText(card), "can be your commander") {
+		return true
+	}
+	// A Background is only a commander next to a creature that chooses one
+	for _, partner := range partners {
+		if strings.Contains(typeLine, "Background") && slices.Contains(partner.Keywords, "Choose a Background") {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpell reports whether a card is an instant or sorcery
+func isSpell(card scryfall.Card) bool {
+	typeLine := frontTypeLine(card)
+	return strings.Contains(typeLine, "Instant") || strings.Contains(typeLine, "Sorcery")
+}
+
+// canPair reports whether two cards may share command
+func canPair(a, b scryfall.Card) bool {
+	partnerA, partnerB := partnerWith(a), partnerWith(b)
+	switch {
+	case partnerA != "" || partnerB != "":
+		return partnerA == b.Name && partnerB == a.Name
+	case hasKeyword(a, b, "Partner"), hasKeyword(a, b, "Friends forever"):
+		return true
+	}
+	return pairsWith(a, b, "Choose a Background", "Background") ||
+		pairsWith(a, b, "Doctor's companion", "Time Lord Doctor")
+}
+
+// hasKeyword reports whether both cards have a keyword
+func hasKeyword(a, b scryfall.Card, keyword string) bool {
+	return slices.Contains(a.Keywords, keyword) && slices.Contains(b.Keywords, keyword)
+}
+
+// pairsWith reports whether one card has the keyword and the other the matching type
+func pairsWith(a, b scryfall.Card, keyword, typeName string) bool {
+	return (slices.Contains(a.Keywords, keyword) && strings.Contains(frontTypeLine(b), typeName)) ||
+		(slices.Contains(b.Keywords, keyword) && strings.Contains(frontTypeLine(a), typeName))
+}
+
+// partnerWith returns the name in a card's "Partner with" ability, if any
+func partnerWith(card scryfall.Card) string {
+	if match := partnerWithRegex.FindStringSubmatch(oracleText(card)); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// frontTypeLine returns the type line of the card's front face
+func frontTypeLine(card scryfall.Card) string {
+	if len(card.CardFaces) > 0 && card.CardFaces[0].TypeLine != "" {
+		return card.CardFaces[0].TypeLine
+	}
+	return card.TypeLine
+}
+
+// oracleText returns the rules text of every face
+func oracleText(card scryfall.Card) string {
+	texts := []string{card.OracleText}
+	for _, face := range card.CardFaces {
+		texts = append(texts, face.OracleText)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// formatColors prints a color identity in WUBRG order, e.g. {U}{R}
+func formatColors(colors []string) string {
+	var out strings.Builder
+	for _, color := range colorOrder {
+		if slices.Contains(colors, color) {
+			out.WriteString("{" + color + "}")
+		}
+	}
+	if out.Len() == 0 {
+		return "colorless"
+	}
+	return out.String()
+}
+
+// mapKeys returns the keys of a set
+func mapKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,197 @@
+package legality
+
+import (
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// commanderDeck builds a 100-card deck around the given command zone entries
+func commanderDeck(command []model.CardEntry, cards ...model.CardEntry) *model.Decklist {
+	return sizedDeck(100, command, cards...)
+}
+
+// sizedDeck fills a deck of the given size with basic lands around the given
+// command zone entries
+func sizedDeck(deckSize int, command []model.CardEntry, cards ...model.CardEntry) *model.Decklist {
+	wastes := scryfall.Card{Name: "Wastes", TypeLine: "Basic Land", Legality: legalIn(scryfall.Legal)}
+	decklist := &model.Decklist{Cards: append(command, cards...)}
+	size := 0
+	for _, entry := range decklist.Cards {
+		if entry.Board() == model.BoardMain {
+			size += entry.Qty
+		}
+	}
+	decklist.Cards = append(decklist.Cards, entry(99, deckSize-size, wastes, ""))
+	return decklist
+}
+
+// commanderViolations checks a deck as Commander and returns its violations as text
+func commanderViolations(t *testing.T, decklist *model.Decklist) []string {
+	return formatViolations(t, "commander", decklist)
+}
+
+// formatViolations checks a deck in a format and returns its violations as text
+func formatViolations(t *testing.T, name string, decklist *model.Decklist) []string {
+	format, err := Lookup(name)
+	require.NoError(t, err)
+
+	var got []string
+	for _, violation := range Check(decklist, format).Violations {
+		got = append(got, violation.String())
+	}
+	return got
+}
+
+func TestCheckCommander(t *testing.T) {
+	legendary := func(name string, identity []string, keywords []string, oracle string) scryfall.Card {
+		return scryfall.Card{Name: name, TypeLine: "Legendary Creature — Human", ColorIdentity: identity,
+			Keywords: keywords, OracleText: oracle, Legality: legalIn(scryfall.Legal)}
+	}
+	krenko := legendary("Krenko, Mob Boss", []string{"R"}, nil, "")
+	bolt := scryfall.Card{Name: "Lightning Bolt", TypeLine: "Instant", ColorIdentity: []string{"R"}, Legality: legalIn(scryfall.Legal)}
+	counterspell := scryfall.Card{Name: "Counterspell", TypeLine: "Instant", ColorIdentity: []string{"U"}, Legality: legalIn(scryfall.Legal)}
+
+	t.Run("legal deck", func(t *testing.T) {
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, krenko, "Commander")}, entry(2, 1, bolt, ""))
+		require.Empty(t, commanderViolations(t, decklist))
+	})
+
+	t.Run("missing commander", func(t *testing.T) {
+		decklist := commanderDeck(nil, entry(2, 1, bolt, ""))
+		require.Equal(t, []string{"deck has no commander: put it in a Commander section"}, commanderViolations(t, decklist))
+	})
+
+	t.Run("color identity", func(t *testing.T) {
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, krenko, "Commander")},
+			entry(2, 1, counterspell, ""),
+			entry(3, 1, counterspell, "Maybeboard"))
+		require.Equal(t, []string{
+			"Line 2, Counterspell: color identity {U} is outside the commander's {R}",
+		}, commanderViolations(t, decklist))
+	})
+
+	t.Run("ineligible commander", func(t *testing.T) {
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, bolt, "Commander")})
+		require.Equal(t, []string{
+			"Line 1, Lightning Bolt: not a legendary creature and cannot be your commander",
+		}, commanderViolations(t, decklist))
+
+		planeswalker := scryfall.Card{Name: "Teferi, Temporal Archmage", TypeLine: "Legendary Planeswalker — Teferi",
+			ColorIdentity: []string{"U"}, OracleText: "Teferi, Temporal Archmage can be your commander.", Legality: legalIn(scryfall.Legal)}
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, planeswalker, "Commander")}, entry(2, 1, counterspell, ""))
+		require.Empty(t, commanderViolations(t, decklist))
+	})
+
+	t.Run("partners", func(t *testing.T) {
+		thrasios := legendary("Thrasios, Triton Hero", []string{"G", "U"}, []string{"Partner"}, "Partner (You can have two commanders if both have partner.)")
+		tymna := legendary("Tymna the Weaver", []string{"W", "B"}, []string{"Partner"}, "Partner (You can have two commanders if both have partner.)")
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, thrasios, "Commander"), entry(2, 1, tymna, "Commander")},
+			entry(3, 1, counterspell, ""))
+		require.Empty(t, commanderViolations(t, decklist))
+
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, thrasios, "Commander"), entry(2, 1, krenko, "Commander")})
+		require.Equal(t, []string{
+			"Line 2, Krenko, Mob Boss: cannot share command with Thrasios, Triton Hero",
+		}, commanderViolations(t, decklist))
+	})
+
+	t.Run("partner with", func(t *testing.T) {
+		pir := legendary("Pir, Imaginative Rascal", []string{"G"}, []string{"Partner with", "Partner"},
+			"Partner with Toothy, Imaginary Friend (When this creature enters, target player may put Toothy into their hand from their library, then shuffle.)")
+		toothy := legendary("Toothy, Imaginary Friend", []string{"U"}, []string{"Partner with", "Partner"},
+			"Partner with Pir, Imaginative Rascal (When this creature enters, target player may put Pir into their hand from their library, then shuffle.)")
+		thrasios := legendary("Thrasios, Triton Hero", []string{"G", "U"}, []string{"Partner"}, "Partner")
+
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, pir, "Commander"), entry(2, 1, toothy, "Commander")})
+		require.Empty(t, commanderViolations(t, decklist))
+
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, pir, "Commander"), entry(2, 1, thrasios, "Commander")})
+		require.Len(t, commanderViolations(t, decklist), 1)
+	})
+
+	t.Run("backgrounds", func(t *testing.T) {
+		wilson := legendary("Wilson, Refined Grizzly", []string{"G"}, []string{"Choose a Background"}, "Choose a Background")
+		background := scryfall.Card{Name: "Raised by Giants", TypeLine: "Legendary Enchantment — Background",
+			ColorIdentity: []string{"G"}, Legality: legalIn(scryfall.Legal)}
+
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, wilson, "Commander"), entry(2, 1, background, "Commander")})
+		require.Empty(t, commanderViolations(t, decklist))
+
+		// A Background can't lead a deck on its own
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, background, "Commander")})
+		require.Equal(t, []string{
+			"Line 1, Raised by Giants: not a legendary creature and cannot be your commander",
+		}, commanderViolations(t, decklist))
+	})
+
+	t.Run("companions", func(t *testing.T) {
+		lurrus := scryfall.Card{Name: "Lurrus of the Dream-Den", TypeLine: "Legendary Creature — Cat Nightmare",
+			ColorIdentity: []string{"W", "B"}, Keywords: []string{"Companion", "Lifelink"}, Legality: legalIn(scryfall.Legal)}
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, krenko, "Commander")},
+			entry(2, 1, lurrus, "Companion"),
+			entry(3, 1, bolt, "Companion"))
+		require.Equal(t, []string{
+			"Line 2, Lurrus of the Dream-Den: color identity {W}{B} is outside the commander's {R}",
+			"Line 3, Lightning Bolt: does not have Companion",
+		}, commanderViolations(t, decklist))
+	})
+}
+
+func TestCheckCommanderStyleFormats(t *testing.T) {
+	goblin := scryfall.Card{Name: "Goblin Bushwhacker", TypeLine: "Creature — Goblin Warrior",
+		ColorIdentity: []string{"R"}, Legality: legalIn(scryfall.Legal)}
+	chandra := scryfall.Card{Name: "Chandra, Torch of Defiance", TypeLine: "Legendary Planeswalker — Chandra",
+		ColorIdentity: []string{"R"}, Legality: legalIn(scryfall.Legal)}
+	bolt := scryfall.Card{Name: "Lightning Bolt", TypeLine: "Instant", ColorIdentity: []string{"R"}, Legality: legalIn(scryfall.Legal)}
+	counterspell := scryfall.Card{Name: "Counterspell", TypeLine: "Instant", ColorIdentity: []string{"U"}, Legality: legalIn(scryfall.Legal)}
+
+	t.Run("pauper commander is led by any creature", func(t *testing.T) {
+		decklist := commanderDeck([]model.CardEntry{entry(1, 1, goblin, "Commander")}, entry(2, 1, bolt, ""))
+		require.Empty(t, formatViolations(t, "paupercommander", decklist))
+		require.Equal(t, []string{
+			"Line 1, Goblin Bushwhacker: not a legendary creature and cannot be your commander",
+		}, commanderViolations(t, decklist))
+
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, chandra, "Commander")})
+		require.Equal(t, []string{
+			"Line 1, Chandra, Torch of Defiance: not a creature and cannot be your commander",
+		}, formatViolations(t, "paupercommander", decklist))
+	})
+
+	t.Run("brawl decks can be led by planeswalkers", func(t *testing.T) {
+		decklist := sizedDeck(60, []model.CardEntry{entry(1, 1, chandra, "Commander")}, entry(2, 1, counterspell, ""))
+		require.Equal(t, []string{
+			"Line 2, Counterspell: color identity {U} is outside the commander's {R}",
+		}, formatViolations(t, "standardbrawl", decklist))
+
+		decklist = commanderDeck([]model.CardEntry{entry(1, 1, chandra, "Commander")})
+		require.Empty(t, formatViolations(t, "brawl", decklist))
+		require.Equal(t, []string{
+			"main deck has 100 card(s), exactly 60 required",
+		}, formatViolations(t, "standardbrawl", decklist))
+	})
+
+	t.Run("oathbreaker needs a planeswalker and a signature spell", func(t *testing.T) {
+		decklist := sizedDeck(60, []model.CardEntry{entry(1, 1, chandra, "Commander"), entry(2, 1, bolt, "Commander")})
+		require.Empty(t, formatViolations(t, "oathbreaker", decklist))
+
+		decklist = sizedDeck(60, []model.CardEntry{entry(1, 1, chandra, "Commander"), entry(2, 1, counterspell, "Commander")})
+		require.Equal(t, []string{
+			"Line 2, Counterspell: color identity {U} is outside the commander's {R}",
+		}, formatViolations(t, "oathbreaker", decklist))
+
+		decklist = sizedDeck(60, []model.CardEntry{entry(1, 1, goblin, "Commander")})
+		require.Equal(t, []string{
+			"deck has 0 signature spell(s) for 1 oathbreaker(s): put one instant or sorcery per oathbreaker in the Commander section",
+			"Line 1, Goblin Bushwhacker: not a planeswalker and cannot be your commander",
+		}, formatViolations(t, "oathbreaker", decklist))
+	})
+
+	t.Run("gladiator has no commander", func(t *testing.T) {
+		decklist := commanderDeck(nil, entry(1, 1, counterspell, ""), entry(2, 1, bolt, ""))
+		require.Empty(t, formatViolations(t, "gladiator", decklist))
+	})
+}
@@ -0,0 +1,86 @@
+package legality
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Format describes the deck construction rules of a format
+type Format struct {
+	Name      string // Format name as used in Scryfall's legalities
+	MinCards  int    // Minimum main deck size
+	MaxCards  int    // Maximum main deck size, or 0 for no limit
+	MaxCopies int    // Copies allowed of each card other than basic lands
+
+	// Commander is who may lead the deck in Commander-style formats, whose
+	// commanders set its color identity; empty for other formats
+	Commander CommandRule
+}
+
+// Singleton reports whether the format allows one copy of each card
+func (f Format) Singleton() bool {
+	return f.MaxCopies == 1
+}
+
+// constructed returns a 60-card format with up to four copies of each card
+func constructed(name string) Format {
+	return Format{Name: name, MinCards: 60, MaxCopies: 4}
+}
+
+// singleton returns a format with exactly size cards and one copy of each
+func singleton(name string, size int) Format {
+	return Format{Name: name, MinCards: size, MaxCards: size, MaxCopies: 1}
+}
+
+// commander returns a singleton format with exactly size cards led by
+// commanders chosen by rule
+func commander(name string, size int, rule CommandRule) Format {
+	format := singleton(name, size)
+	format.Commander = rule
+	return format
+}
+
+// formats are the formats Scryfall reports legalities for
+var formats = map[string]Format{
+	"standard":        constructed("standard"),
+	"future":          constructed("future"),
+	"historic":        constructed("historic"),
+	"timeless":        constructed("timeless"),
+	"alchemy":         constructed("alchemy"),
+	"pioneer":         constructed("pioneer"),
+	"modern":          constructed("modern"),
+	"legacy":          constructed("legacy"),
+	"vintage":         constructed("vintage"),
+	"pauper":          constructed("pauper"),
+	"penny":           constructed("penny"),
+	"oldschool":       constructed("oldschool"),
+	"premodern":       constructed("premodern"),
+	"commander":       commander("commander", 100, CommandLegendary),
+	"duel":            commander("duel", 100, CommandLegendary),
+	"paupercommander": commander("paupercommander", 100, CommandCreature),
+	"predh":           commander("predh", 100, CommandLegendary),
+	"brawl":           commander("brawl", 100, CommandBrawl),
+	"standardbrawl":   commander("standardbrawl", 60, CommandBrawl),
+	"oathbreaker":     commander("oathbreaker", 60, CommandOathbreaker),
+	"gladiator":       singleton("gladiator", 100), // No commander, only the singleton and size rules
+}
+
+// Lookup returns the named format
+func Lookup(name string) (Format, error) {
+	format, ok := formats[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown format '%s': use one of %s", name, strings.Join(Names(), ", "))
+	}
+	return format, nil
+}
+
+// Names lists the known formats in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
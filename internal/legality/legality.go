@@ -0,0 +1,206 @@
+// Package legality checks a resolved decklist against a format's banned and
+// restricted lists, copy limits and deck size, and Commander decks against
+// their command zone and color identity rules.
+package legality
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Violation kinds
+const (
+	KindBanned     = "banned"
+	KindRestricted = "restricted"
+	KindNotLegal   = "not_legal"
+	KindCopyLimit  = "copy_limit"
+	KindDeckSize   = "deck_size"
+	KindUnresolved = "unresolved" // Card data is needed to check the card
+)
+
+// Violation is a single rule the decklist breaks
+type Violation struct {
+	Line    int    `json:"line,omitempty"`
+	Card    string `json:"card,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as a single line
+func (v Violation) String() string {
+	if v.Card == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("Line %d, %s: %s", v.Line, v.Card, v.Message)
+}
+
+// Result is the outcome of checking a decklist against a format
+type Result struct {
+	Format     string      `json:"format"`
+	Cards      int         `json:"cards"`     // Main deck size
+	Sideboard  int         `json:"sideboard"` // Sideboard size
+	Violations []Violation `json:"violations"`
+}
+
+// Legal reports whether the decklist breaks no rules
+func (r *Result) Legal() bool {
+	return len(r.Violations) == 0
+}
+
+// Check checks a resolved decklist against a format. Maybeboard entries are
+// ignored; sideboard entries count towards copy limits but not deck size.
+func Check(decklist *model.Decklist, format Format) *Result {
+	result := &Result{Format: format.Name, Violations: []Violation{}}
+
+	var names []string
+	copies := map[string]int{}
+	first := map[string]model.CardEntry{}
+	for _, entry := range decklist.Cards {
+		board := entry.Board()
+		if board == model.BoardNone {
+			continue
+		}
+		if board == model.BoardSideboard {
+			result.Sideboard += entry.Qty
+		} else {
+			result.Cards += entry.Qty
+		}
+
+		if !entry.Resolved() {
+			result.add(entry, KindUnresolved, "could not check legality: card was not resolved")
+			continue
+		}
+		result.checkStatus(entry, format)
+
+		name := entry.Card.Name
+		if _, seen := copies[name]; !seen {
+			names = append(names, name)
+			first[name] = entry
+		}
+		copies[name] += entry.Qty
+	}
+
+	// Copy limits apply to the card name across every printing
+	for _, name := range names {
+		entry := first[name]
+		status, _ := entry.Card.Legality.Status(format.Name)
+		limit := copyLimit(entry.Card, format)
+		switch {
+		case status == scryfall.Restricted && copies[name] > 1:
+			result.add(entry, KindRestricted, fmt.Sprintf("restricted to 1 copy in %s, found %d", format.Name, copies[name]))
+		case limit > 0 && copies[name] > limit:
+			result.add(entry, KindCopyLimit, fmt.Sprintf("%d %s allowed in %s, found %d", limit, plural(limit, "copy", "copies"), format.Name, copies[name]))
+		}
+	}
+
+	result.checkDeckSize(format)
+	if format.Commander != "" {
+		result.checkCommander(decklist, format.Commander)
+	}
+	return result
+}
+
+// checkStatus reports banned and not-legal printings
+func (r *Result) checkStatus(entry model.CardEntry, format Format) {
+	status, _ := entry.Card.Legality.Status(format.Name)
+	switch status {
+	case scryfall.Banned:
+		r.add(entry, KindBanned, "banned in "+format.Name)
+	case scryfall.NotLegal, "":
+		r.add(entry, KindNotLegal, "not legal in "+format.Name)
+	}
+}
+
+// checkDeckSize reports a main deck that is too small or too large
+func (r *Result) checkDeckSize(format Format) {
+	var message string
+	switch {
+	case format.MaxCards == format.MinCards && r.Cards != format.MinCards:
+		message = fmt.Sprintf("main deck has %d card(s), exactly %d required", r.Cards, format.MinCards)
+	case r.Cards < format.MinCards:
+		message = fmt.Sprintf("main deck has %d card(s), at least %d required", r.Cards, format.MinCards)
+	case format.MaxCards > 0 && r.Cards > format.MaxCards:
+		message = fmt.Sprintf("main deck has %d card(s), at most %d allowed", r.Cards, format.MaxCards)
+	default:
+		return
+	}
+	r.Violations = append(r.Violations, Violation{Kind: KindDeckSize, Message: message})
+}
+
+// add records a violation for a decklist entry
+func (r *Result) add(entry model.CardEntry, kind, message string) {
+	r.Violations = append(r.Violations, Violation{
+		Line:    entry.Line,
+		Card:    entry.DisplayName(),
+		ID:      entry.ID,
+		Kind:    kind,
+		Message: message,
+	})
+}
+
+// WriteText writes a human-readable summary of the result
+func (r *Result) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("%s: %d card(s) in the main deck, %d in the sideboard\n", r.Format, r.Cards, r.Sideboard)
+	if r.Legal() {
+		printf("\n✅ Deck is legal in %s\n", r.Format)
+		return err
+	}
+	printf("\nViolations:\n")
+	for _, violation := range r.Violations {
+		printf("   • %s\n", violation)
+	}
+	printf("\n❌ Deck has %d violation(s) in %s\n", len(r.Violations), r.Format)
+	return err
+}
+
+// anyNumberRegex and upToRegex match the oracle text exceptions to copy limits,
+// e.g. Relentless Rats and Seven Dwarves
+var (
+	anyNumberRegex = regexp.MustCompile(`(?i)a deck can have any number of cards named`)
+	upToRegex      = regexp.MustCompile(`(?i)a deck can have up to (\w+) cards named`)
+)
+
+// numberWords spells out the limits used in "up to N cards named" text
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// copyLimit returns how many copies of a card the format allows, or 0 for any number
+func copyLimit(card scryfall.Card, format Format) int {
+	if strings.HasPrefix(card.TypeLine, "Basic ") || anyNumberRegex.MatchString(card.OracleText) {
+		return 0
+	}
+	if match := upToRegex.FindStringSubmatch(card.OracleText); match != nil {
+		word := strings.ToLower(match[1])
+		if n, ok := numberWords[word]; ok {
+			return n
+		}
+		if n, err := strconv.Atoi(word); err == nil {
+			return n
+		}
+	}
+	return format.MaxCopies
+}
+
+// plural picks the singular or plural form of a word
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
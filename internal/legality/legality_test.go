@@ -0,0 +1,138 @@
+package legality
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// entry builds a resolved decklist entry
+func entry(line, qty int, card scryfall.Card, section string) model.CardEntry {
+	return model.CardEntry{
+		Line:    line,
+		Qty:     qty,
+		ID:      card.Name,
+		Section: section,
+		Card:    card,
+		Faces:   []model.Face{{Name: card.Name}},
+	}
+}
+
+// legalIn returns legalities with the same status in modern, vintage and the
+// Commander-style formats
+func legalIn(status string) scryfall.Legalities {
+	return scryfall.Legalities{Modern: status, Vintage: status, Commander: status, Paupercommander: status,
+		Brawl: status, Standardbrawl: status, Oathbreaker: status, Gladiator: status}
+}
+
+func TestLookup(t *testing.T) {
+	format, err := Lookup("Commander")
+	require.NoError(t, err)
+	require.True(t, format.Singleton())
+	require.Equal(t, 100, format.MinCards)
+
+	_, err = Lookup("tiny-leaders")
+	require.ErrorContains(t, err, "unknown format 'tiny-leaders'")
+	require.Contains(t, Names(), "pauper")
+}
+
+func TestCheck(t *testing.T) {
+	bolt := scryfall.Card{Name: "Lightning Bolt", TypeLine: "Instant", Legality: legalIn(scryfall.Legal)}
+	mountain := scryfall.Card{Name: "Mountain", TypeLine: "Basic Land — Mountain", Legality: legalIn(scryfall.Legal)}
+	rats := scryfall.Card{Name: "Relentless Rats", TypeLine: "Creature — Rat", Legality: legalIn(scryfall.Legal),
+		OracleText: "Relentless Rats gets +1/+1 for each other creature you control named Relentless Rats.\nA deck can have any number of cards named Relentless Rats."}
+	dwarves := scryfall.Card{Name: "Seven Dwarves", TypeLine: "Creature — Dwarf", Legality: legalIn(scryfall.Legal),
+		OracleText: "A deck can have up to seven cards named Seven Dwarves."}
+	lotus := scryfall.Card{Name: "Black Lotus", TypeLine: "Artifact",
+		Legality: scryfall.Legalities{Modern: scryfall.NotLegal, Vintage: scryfall.Restricted, Commander: scryfall.Banned}}
+	grief := scryfall.Card{Name: "Grief", TypeLine: "Creature — Elemental Incarnation",
+		Legality: scryfall.Legalities{Modern: scryfall.Banned, Vintage: scryfall.Legal, Commander: scryfall.Legal}}
+
+	t.Run("legal deck", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			entry(1, 4, bolt, ""),
+			entry(2, 20, mountain, ""),
+			entry(3, 30, rats, ""),
+			entry(4, 6, dwarves, ""),
+			entry(5, 15, mountain, "Sideboard"),
+			entry(6, 4, lotus, "Maybeboard"),
+		}}
+		format, _ := Lookup("modern")
+		result := Check(decklist, format)
+		require.True(t, result.Legal(), result.Violations)
+		require.Equal(t, 60, result.Cards)
+		require.Equal(t, 15, result.Sideboard)
+	})
+
+	t.Run("constructed violations", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			entry(1, 3, bolt, ""),
+			entry(2, 2, bolt, "Sideboard"), // Copy limits span the sideboard
+			entry(3, 1, lotus, ""),
+			entry(4, 1, grief, ""),
+			entry(5, 8, dwarves, ""),
+			{Line: 6, Qty: 1, ID: "missing", Name: "Missing Card"},
+		}}
+		format, _ := Lookup("modern")
+		result := Check(decklist, format)
+		require.False(t, result.Legal())
+
+		var got []string
+		for _, violation := range result.Violations {
+			got = append(got, violation.String())
+		}
+		require.Equal(t, []string{
+			"Line 3, Black Lotus: not legal in modern",
+			"Line 4, Grief: banned in modern",
+			"Line 6, Missing Card: could not check legality: card was not resolved",
+			"Line 1, Lightning Bolt: 4 copies allowed in modern, found 5",
+			"Line 5, Seven Dwarves: 7 copies allowed in modern, found 8",
+			"main deck has 14 card(s), at least 60 required",
+		}, got)
+	})
+
+	t.Run("restricted cards", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			entry(1, 1, lotus, ""),
+			entry(2, 1, lotus, "Sideboard"),
+			entry(3, 59, mountain, ""),
+		}}
+		format, _ := Lookup("vintage")
+		result := Check(decklist, format)
+		require.Len(t, result.Violations, 1)
+		require.Equal(t, KindRestricted, result.Violations[0].Kind)
+		require.Equal(t, "restricted to 1 copy in vintage, found 2", result.Violations[0].Message)
+	})
+
+	t.Run("singleton formats need an exact deck size", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			entry(1, 1, scryfall.Card{Name: "Krenko, Mob Boss", TypeLine: "Legendary Creature — Goblin Warrior",
+				ColorIdentity: []string{"R"}, Legality: legalIn(scryfall.Legal)}, "Commander"),
+			entry(2, 2, bolt, ""),
+			entry(3, 90, mountain, ""),
+		}}
+		format, _ := Lookup("commander")
+		result := Check(decklist, format)
+
+		var kinds []string
+		for _, violation := range result.Violations {
+			kinds = append(kinds, violation.Kind)
+		}
+		require.Equal(t, []string{KindCopyLimit, KindDeckSize}, kinds)
+		require.Equal(t, "1 copy allowed in commander, found 2", result.Violations[0].Message)
+		require.Equal(t, "main deck has 93 card(s), exactly 100 required", result.Violations[1].Message)
+	})
+
+	t.Run("text output", func(t *testing.T) {
+		decklist := &model.Decklist{Cards: []model.CardEntry{entry(1, 1, lotus, "")}}
+		format, _ := Lookup("commander")
+
+		var out bytes.Buffer
+		require.NoError(t, Check(decklist, format).WriteText(&out))
+		require.Contains(t, out.String(), "   • Line 1, Black Lotus: banned in commander\n")
+		require.Contains(t, out.String(), "❌ Deck has 3 violation(s) in commander")
+	})
+}
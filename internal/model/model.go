@@ -0,0 +1,146 @@
+// Package model holds the domain types shared by every stage of deck
+// generation: the parser builds a Decklist, the resolver fills in card data,
+// renderers consume it and reporters summarize it.
+package model
+
+import (
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Card finishes as named by Scryfall
+const (
+	FinishNonfoil = "nonfoil"
+	FinishFoil    = "foil"
+	FinishEtched  = "etched"
+)
+
+// MainSection is the section used for entries without an explicit section
+const MainSection = "Mainboard"
+
+// Decklist is a parsed decklist and, once resolved, its card data
+type Decklist struct {
+	Name   string // Deck name, used as the output title
+	Source string // Path of the decklist file
+	Cards  []CardEntry
+}
+
+// CardEntry is a single decklist line
+type CardEntry struct {
+	Line    int // Line in the decklist file, when parsed from one
+	Qty     int
+	Name    string // Card name as written in the decklist
+	ID      string // Scryfall ID of the printing
+	Section string // Optional deck section such as Commander or Sideboard
+	Finish  string // Optional finish: nonfoil, foil or etched
+	Lang    string // Optional language code such as ja; defaults to the first preferred language
+
+	Card  scryfall.Card // Card data from Scryfall API, populated by the resolver
+	Faces []Face        // Printable faces, populated by the resolver
+	Pages []int         // Output pages, populated by page-based renderers
+	Err   error         // Failure to resolve or render the entry
+}
+
+// Face is a single printable card face whose image has been cached locally
+type Face struct {
+	Name      string
+	ImagePath string
+	Cached    bool     // Image was already in the local cache
+	Back      bool     // Printed on the back of the preceding face, for duplex output
+	Text      []string // Printed as text instead of an image, such as a checklist insert
+	Err       error    // Image failure; the face is still printed without art
+}
+
+// Resolved reports whether the entry's card data and faces have been resolved
+func (c CardEntry) Resolved() bool {
+	return len(c.Faces) > 0
+}
+
+// HasErrors reports whether the entry failed to resolve or render, or is
+// missing a face image
+func (c CardEntry) HasErrors() bool {
+	if c.Err != nil || !c.Resolved() {
+		return true
+	}
+	for _, face := range c.Faces {
+		if face.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the best known name for the entry
+func (c CardEntry) DisplayName() string {
+	if c.Card.Name != "" {
+		return c.Card.Name
+	}
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ID
+}
+
+// SectionName returns the entry's section, defaulting to MainSection
+func (c CardEntry) SectionName() string {
+	if c.Section == "" {
+		return MainSection
+	}
+	return c.Section
+}
+
+// Board is the part of a deck an entry belongs to
+type Board int
+
+// Boards
+const (
+	BoardMain      Board = iota // Main deck, including commanders
+	BoardSideboard              // Sideboard and companion
+	BoardNone                   // Maybeboard and tokens, not part of the deck
+)
+
+// Board classifies the entry's Archidekt-style section
+func (c CardEntry) Board() Board {
+	switch strings.ToLower(c.Section) {
+	case "maybeboard", "considering", "tokens", "token":
+		return BoardNone
+	case "sideboard", "companion":
+		return BoardSideboard
+	default:
+		return BoardMain
+	}
+}
+
+// Summary describes a finished generation run
+type Summary struct {
+	Deck       string `json:"deck,omitempty"`   // Deck name
+	Format     string `json:"format,omitempty"` // Output format name
+	OutputPath string `json:"output_path"`
+	Entries    int    `json:"entries"`  // Decklist lines
+	Resolved   int    `json:"resolved"` // Lines with card data
+	Failed     int    `json:"failed"`   // Lines with any error
+	Copies     int    `json:"copies"`   // Total card copies in the decklist
+	Pages      int    `json:"pages"`    // Card faces printed, counting every copy
+}
+
+// NewSummary counts the entries, copies and printed faces of a decklist
+func NewSummary(decklist *Decklist, format, outputPath string) Summary {
+	summary := Summary{
+		Deck:       decklist.Name,
+		Format:     format,
+		OutputPath: outputPath,
+		Entries:    len(decklist.Cards),
+	}
+	for _, entry := range decklist.Cards {
+		summary.Copies += entry.Qty
+		if entry.HasErrors() {
+			summary.Failed++
+		}
+		if entry.Resolved() {
+			summary.Resolved++
+			summary.Pages += len(entry.Faces) * entry.Qty
+		}
+	}
+	return summary
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardEntry(t *testing.T) {
+	t.Run("display name prefers resolved card data", func(t *testing.T) {
+		entry := CardEntry{ID: "id", Name: "Lightning Bolt"}
+		require.Equal(t, "Lightning Bolt", entry.DisplayName())
+
+		entry.Card = scryfall.Card{Name: "Lightning Bolt (Resolved)"}
+		require.Equal(t, "Lightning Bolt (Resolved)", entry.DisplayName())
+
+		require.Equal(t, "id", CardEntry{ID: "id"}.DisplayName())
+	})
+
+	t.Run("errors include unresolved entries and missing images", func(t *testing.T) {
+		entry := CardEntry{ID: "bolt", Faces: []Face{{Name: "Lightning Bolt"}}}
+		require.False(t, entry.HasErrors())
+		require.True(t, CardEntry{ID: "missing"}.HasErrors())
+
+		entry.Faces[0].Err = errors.New("no image")
+		require.True(t, entry.HasErrors())
+	})
+
+	t.Run("boards from sections", func(t *testing.T) {
+		require.Equal(t, BoardMain, CardEntry{}.Board())
+		require.Equal(t, BoardMain, CardEntry{Section: "Commander"}.Board())
+		require.Equal(t, BoardSideboard, CardEntry{Section: "Sideboard"}.Board())
+		require.Equal(t, BoardNone, CardEntry{Section: "Maybeboard"}.Board())
+	})
+
+	t.Run("section defaults to mainboard", func(t *testing.T) {
+		require.Equal(t, MainSection, CardEntry{}.SectionName())
+		require.Equal(t, "Sideboard", CardEntry{Section: "Sideboard"}.SectionName())
+	})
+}
+
+func TestNewSummary(t *testing.T) {
+	decklist := &Decklist{Name: "Delver", Cards: []CardEntry{
+		{Qty: 4, ID: "bolt", Faces: []Face{{Name: "Lightning Bolt"}}},
+		{Qty: 2, ID: "delver", Faces: []Face{{Name: "Delver of Secrets"}, {Name: "Insectile Aberration"}}},
+		{Qty: 1, ID: "missing"},
+	}}
+
+	summary := NewSummary(decklist, "pdf", "delver.pdf")
+	require.Equal(t, Summary{
+		Deck:       "Delver",
+		Format:     "pdf",
+		OutputPath: "delver.pdf",
+		Entries:    3,
+		Resolved:   2,
+		Failed:     1,
+		Copies:     7,
+		Pages:      8,
+	}, summary)
+}
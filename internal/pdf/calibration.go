@@ -0,0 +1,171 @@
+package pdf
+
+import (
+	"fmt"
+
+	"github.com/signintech/gopdf"
+)
+
+// Calibration corrects printer misalignment. Offsets are in mm and scales are
+// ratios, so the zero value applies no correction.
+type Calibration struct {
+	OffsetX     float64 // Shift applied to every page
+	OffsetY     float64
+	BackOffsetX float64 // Additional shift applied to the backs of duplex cards
+	BackOffsetY float64
+	ScaleX      float64 // Scale correction applied to every page (0 means 1.0)
+	ScaleY      float64
+}
+
+// Validate checks that the calibration values are usable
+func (c Calibration) Validate() error {
+	if c.ScaleX < 0 || c.ScaleY < 0 {
+		return fmt.Errorf("scale corrections must be positive, got %.3f x %.3f", c.ScaleX, c.ScaleY)
+	}
+	return nil
+}
+
+// scales returns the effective horizontal and vertical scale factors
+func (c Calibration) scales() (float64, float64) {
+	scaleX, scaleY := c.ScaleX, c.ScaleY
+	if scaleX == 0 {
+		scaleX = 1
+	}
+	if scaleY == 0 {
+		scaleY = 1
+	}
+	return scaleX, scaleY
+}
+
+// point maps a page position to its corrected position
+func (c Calibration) point(x, y float64, back bool) (float64, float64) {
+	scaleX, scaleY := c.scales()
+	x, y = x*scaleX+c.OffsetX, y*scaleY+c.OffsetY
+	if back {
+		x, y = x+c.BackOffsetX, y+c.BackOffsetY
+	}
+	return x, y
+}
+
+// size maps a width and height to their corrected values
+func (c Calibration) size(width, height float64) (float64, float64) {
+	scaleX, scaleY := c.scales()
+	return width * scaleX, height * scaleY
+}
+
+// Calibration sheet markings in mm
+const (
+	calibrationGrid       = 10.0 // Grid spacing
+	calibrationRulerInset = 15.0 // Distance of the rulers from the page edge
+	calibrationCrossSize  = 10.0 // Half length of each crosshair
+	calibrationCornerGap  = 25.0 // Distance of corner crosshairs from the page edge
+)
+
+// calibrationSheet draws corrected markings on one side of the test sheet.
+// Back pages are mirrored horizontally so that, with long-edge duplex printing,
+// every mark lands directly behind its front counterpart.
+type calibrationSheet struct {
+	doc         *gopdf.GoPdf
+	calibration Calibration
+	width       float64
+	height      float64
+	back        bool
+}
+
+// line draws a corrected line between two page positions
+func (s *calibrationSheet) line(x1, y1, x2, y2 float64) {
+	if s.back {
+		x1, x2 = s.width-x1, s.width-x2
+	}
+	x1, y1 = s.calibration.point(x1, y1, s.back)
+	x2, y2 = s.calibration.point(x2, y2, s.back)
+	s.doc.Line(x1, y1, x2, y2)
+}
+
+// crosshair draws a cross centered on a page position
+func (s *calibrationSheet) crosshair(x, y float64) {
+	s.line(x-calibrationCrossSize, y, x+calibrationCrossSize, y)
+	s.line(x, y-calibrationCrossSize, x, y+calibrationCrossSize)
+	// Small box around the center for sub-millimetre comparison
+	s.line(x-1, y-1, x+1, y-1)
+	s.line(x+1, y-1, x+1, y+1)
+	s.line(x+1, y+1, x-1, y+1)
+	s.line(x-1, y+1, x-1, y-1)
+}
+
+// draw renders the grid, rulers and crosshairs
+func (s *calibrationSheet) draw() {
+	// Grid centered on the page so it is symmetric on both sides
+	s.doc.SetLineWidth(0.1)
+	s.doc.SetStrokeColor(190, 190, 190)
+	centerX, centerY := s.width/2, s.height/2
+	for x := centerX; x >= 0; x -= calibrationGrid {
+		s.line(x, 0, x, s.height)
+		s.line(s.width-x, 0, s.width-x, s.height)
+	}
+	for y := centerY; y >= 0; y -= calibrationGrid {
+		s.line(0, y, s.width, y)
+		s.line(0, s.height-y, s.width, s.height-y)
+	}
+
+	// Front markings are black, back markings red, so both show against a light
+	if s.back {
+		s.doc.SetStrokeColor(220, 0, 0)
+	} else {
+		s.doc.SetStrokeColor(0, 0, 0)
+	}
+
+	// Millimetre rulers along the top and left edges
+	s.doc.SetLineWidth(0.15)
+	top, left := calibrationRulerInset, calibrationRulerInset
+	s.line(left, top, s.width-left, top)
+	s.line(left, top, left, s.height-top)
+	for mm := 0; float64(mm) <= s.width-2*left; mm++ {
+		x := left + float64(mm)
+		s.line(x, top, x, top+rulerTick(mm))
+	}
+	for mm := 0; float64(mm) <= s.height-2*top; mm++ {
+		y := top + float64(mm)
+		s.line(left, y, left+rulerTick(mm), y)
+	}
+
+	// Crosshairs at the center and near each corner
+	s.doc.SetLineWidth(0.2)
+	s.crosshair(centerX, centerY)
+	s.crosshair(calibrationCornerGap, calibrationCornerGap)
+	s.crosshair(s.width-calibrationCornerGap, calibrationCornerGap)
+	s.crosshair(calibrationCornerGap, s.height-calibrationCornerGap)
+	s.crosshair(s.width-calibrationCornerGap, s.height-calibrationCornerGap)
+}
+
+// rulerTick returns the tick length for a millimetre mark
+func rulerTick(mm int) float64 {
+	switch {
+	case mm%10 == 0:
+		return 5.0
+	case mm%5 == 0:
+		return 3.5
+	default:
+		return 2.0
+	}
+}
+
+// GenerateCalibration writes a two-page duplex test sheet with rulers,
+// crosshairs and a grid, applying the current calibration so corrections can
+// be verified by printing the sheet again.
+func (g *Generator) GenerateCalibration() error {
+	width, height := g.TotalWidth(), g.TotalHeight()
+	if g.pageSize.IsSheet() {
+		width, height = g.pageSize.Width, g.pageSize.Height
+	}
+
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: gopdf.Rect{W: width, H: height}})
+	for _, back := range []bool{false, true} {
+		doc.AddPage()
+		sheet := &calibrationSheet{doc: doc, calibration: g.calibration, width: width, height: height, back: back}
+		sheet.draw()
+	}
+
+	return doc.WritePdf(g.outputPath)
+}
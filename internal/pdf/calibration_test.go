@@ -0,0 +1,130 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/signintech/gopdf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalibration(t *testing.T) {
+	t.Run("zero value applies no correction", func(t *testing.T) {
+		var calibration Calibration
+		x, y := calibration.point(10, 20, true)
+		require.Equal(t, 10.0, x)
+		require.Equal(t, 20.0, y)
+
+		width, height := calibration.size(63, 88)
+		require.Equal(t, 63.0, width)
+		require.Equal(t, 88.0, height)
+	})
+
+	t.Run("back offsets only apply to back pages", func(t *testing.T) {
+		calibration := Calibration{OffsetX: 1, OffsetY: -1, BackOffsetX: 0.5, BackOffsetY: 2}
+
+		x, y := calibration.point(10, 10, false)
+		require.Equal(t, 11.0, x)
+		require.Equal(t, 9.0, y)
+
+		x, y = calibration.point(10, 10, true)
+		require.Equal(t, 11.5, x)
+		require.Equal(t, 11.0, y)
+	})
+
+	t.Run("scale corrections", func(t *testing.T) {
+		calibration := Calibration{ScaleX: 1.01, ScaleY: 0.99}
+		x, y := calibration.point(100, 100, false)
+		require.InDelta(t, 101.0, x, 0.0001)
+		require.InDelta(t, 99.0, y, 0.0001)
+
+		width, height := calibration.size(63, 88)
+		require.InDelta(t, 63.63, width, 0.0001)
+		require.InDelta(t, 87.12, height, 0.0001)
+	})
+
+	t.Run("negative scale is rejected", func(t *testing.T) {
+		require.NoError(t, Calibration{}.Validate())
+		require.Error(t, Calibration{ScaleX: -1}.Validate())
+	})
+}
+
+func TestGenerateCalibration(t *testing.T) {
+	g := NewGenerator(0).(*Generator)
+	outputPath := filepath.Join(t.TempDir(), "calibration.pdf")
+	g.SetOutputPath(outputPath)
+	g.SetPageSize(PageSizeA4)
+	g.SetCalibration(Calibration{BackOffsetX: 1.5})
+
+	require.NoError(t, g.GenerateCalibration())
+
+	doc := gopdf.GoPdf{}
+	doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: *gopdf.PageSizeA4})
+	require.NoError(t, doc.ImportPagesFromSource(outputPath, "/MediaBox"))
+	require.Equal(t, 2, doc.GetNumberOfPages())
+}
+
+// pageRects returns the rectangles drawn on each page of an uncompressed PDF,
+// one content stream per page in page order
+func pageRects(t *testing.T, path string) [][]string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	rectPattern := regexp.MustCompile(`[-\d.]+ [-\d.]+ [-\d.]+ [-\d.]+ re`)
+	var pages [][]string
+	for _, stream := range regexp.MustCompile(`(?s)stream\n(.*?)endstream`).FindAllSubmatch(data, -1) {
+		if rects := rectPattern.FindAllString(string(stream[1]), -1); len(rects) > 0 {
+			pages = append(pages, rects)
+		}
+	}
+	return pages
+}
+
+func TestCalibratedPlacement(t *testing.T) {
+	calibration := Calibration{OffsetX: 1, BackOffsetX: 10}
+
+	t.Run("single-sided decks keep front offsets on every page", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		g.SetCalibration(calibration)
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		g.SetOutputPath(outputPath)
+		cards := testDeck(t, 1)
+		cards[0].Qty = 2
+		require.NoError(t, g.Start(&model.Decklist{Cards: cards}))
+		g.doc.SetNoCompression()
+		require.NoError(t, g.AddCard(cards[0]))
+		require.NoError(t, g.Finish())
+
+		pages := pageRects(t, outputPath)
+		require.Len(t, pages, 2)
+		require.True(t, strings.HasPrefix(pages[0][0], "2.83 "), "front offset applied: %s", pages[0][0])
+		require.Equal(t, pages[0], pages[1])
+	})
+
+	t.Run("duplex decks move only the backs", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		g.SetCalibration(calibration)
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		g.SetOutputPath(outputPath)
+		cards := testDeck(t, 2)
+		cards[0].Qty = 1
+		cards[1].Qty = 1
+		cards[1].Faces = append(cards[1].Faces, model.Face{Name: "Back", ImagePath: cards[1].Faces[0].ImagePath, Back: true})
+		require.NoError(t, g.Start(&model.Decklist{Cards: cards}))
+		g.doc.SetNoCompression()
+		for _, card := range cards {
+			require.NoError(t, g.AddCard(card))
+		}
+		require.NoError(t, g.Finish())
+
+		// Single, blank back, front, back: the blank page draws nothing
+		pages := pageRects(t, outputPath)
+		require.Len(t, pages, 3)
+		require.Equal(t, pages[0], pages[1], "fronts keep the front offsets")
+		require.True(t, strings.HasPrefix(pages[2][0], "31.18 "), "back offset applied: %s", pages[2][0])
+	})
+}
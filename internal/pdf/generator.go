@@ -1,46 +1,58 @@
 package pdf
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
 	"github.com/rs/zerolog/log"
 	"github.com/signintech/gopdf"
 )
 
-// PDFGenerator interface for generating PDFs
+// PDFGenerator interface for generating PDFs.
+// Cards are added one at a time by a render driver; Finish writes the file.
 type PDFGenerator interface {
 	SetOutputPath(path string)
-	GeneratePDF(decklist *Decklist, progress Reporter) error
+	SetCardSize(size CardSize)
+	SetPageSize(size PageSize)
+	SetCalibration(calibration Calibration)
+	SetTextPage(page TextPage)
+	Start(decklist *model.Decklist) error
+	AddCard(entry model.CardEntry) error
+	Finish() error
+	GenerateCalibration() error
 }
 
 // Generator handles PDF creation with bleed margins
 type Generator struct {
 	bleedAmount float64
 	outputPath  string
-}
+	cardSize    CardSize
+	pageSize    PageSize
+	calibration Calibration
 
-// Decklist represents a parsed decklist from CSV
-type Decklist struct {
-	Cards []CardEntry
-}
+	// Document state between Start and Finish
+	doc     *gopdf.GoPdf
+	pages   *pageWriter
+	outline *outlineBuilder
+	images  imageStream
 
-// CardEntry represents a single card in the decklist
-type CardEntry struct {
-	Qty  int
-	ID   string
-	Card scryfall.Card
-}
+	// Pages written by the most recent AddCard call
+	cardPages []int
 
-// Reporter interface for progress reporting
-type Reporter interface {
-	StartUnified(description string, total int)
-	UpdateStage(description string)
-	AddError(cardName, errorMsg string)
-	Finish(decklist interface{}, outputPath string)
+	// Bookmark for the face being written, added on its first page
+	bookmark *bookmark
+
+	// Optional page of text after the cards
+	textPage TextPage
+
+	// Whether the text font has been added to the document
+	fontLoaded bool
 }
 
 // MTG card dimensions in mm
@@ -53,6 +65,7 @@ const (
 func NewGenerator(bleedAmount float64) PDFGenerator {
 	return &Generator{
 		bleedAmount: bleedAmount,
+		cardSize:    CardSizeStandard,
 	}
 }
 
@@ -61,14 +74,31 @@ func (g *Generator) SetOutputPath(path string) {
 	g.outputPath = path
 }
 
-// TotalWidth returns the total page width including bleed
+// SetCardSize sets the card size used for regular cards.
+// Oversized cards always use CardSizeOversized.
+func (g *Generator) SetCardSize(size CardSize) {
+	g.cardSize = size
+}
+
+// SetPageSize sets the output page size. A sheet size lays cards out in a
+// grid; the zero PageSize prints one card per page.
+func (g *Generator) SetPageSize(size PageSize) {
+	g.pageSize = size
+}
+
+// SetCalibration sets the printer offset and scale corrections applied to every page
+func (g *Generator) SetCalibration(calibration Calibration) {
+	g.calibration = calibration
+}
+
+// TotalWidth returns the total card width including bleed
 func (g *Generator) TotalWidth() float64 {
-	return CardWidth + (2 * g.bleedAmount)
+	return g.cardSize.Width + (2 * g.bleedAmount)
 }
 
-// TotalHeight returns the total page height including bleed
+// TotalHeight returns the total card height including bleed
 func (g *Generator) TotalHeight() float64 {
-	return CardHeight + (2 * g.bleedAmount)
+	return g.cardSize.Height + (2 * g.bleedAmount)
 }
 
 // ImagePosition returns the X,Y coordinates where the card image should be positioned
@@ -76,195 +106,272 @@ func (g *Generator) ImagePosition() (float64, float64) {
 	return g.bleedAmount, g.bleedAmount
 }
 
-// GeneratePDF creates a PDF from the decklist
-func (g *Generator) GeneratePDF(decklist *Decklist, progress Reporter) error {
-	// Create cache directory for images
-	cacheDir := ".card_cache"
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
+// Start begins a new output document for the decklist.
+// Each page is written to the document as soon as its card is added, so
+// per-card page buffers are never accumulated. Copies of a card share a
+// single embedded image, which is streamed from disk when the document is
+// finished so that images are never held in memory together. Decks with back faces are laid out for duplex
+// printing: every front page is followed by a back page, left blank behind
+// single-faced cards.
+func (g *Generator) Start(decklist *model.Decklist) error {
+	g.doc = g.newDocument()
+	g.doc.SetInfo(documentInfo(decklist, time.Now()))
+	g.fontLoaded = false
+	g.images = imageStream{}
+	g.pages = newPageWriter(g.doc, g.pageSize, g.bleedAmount)
+	g.pages.duplex = hasBackFaces(decklist)
+	g.outline = newOutlineBuilder(g.doc, g.TotalHeight(), hasSections(decklist))
+	return nil
+}
 
-	pages := [][]byte{}
+// bookmark is an outline entry waiting for the first page of its face
+type bookmark struct {
+	section string
+	key     string
+	title   string
+}
 
-	// Initialize progress reporting
-	if progress != nil {
-		totalOps := calculateTotalOperations(decklist)
-		progress.StartUnified("Starting PDF generation", totalOps)
+// hasBackFaces reports whether any card has a face printed behind another,
+// in which case the whole deck is laid out for duplex printing
+func hasBackFaces(decklist *model.Decklist) bool {
+	for _, entry := range decklist.Cards {
+		for _, face := range entry.Faces {
+			if face.Back {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	// Process each card entry
-	for _, cardEntry := range decklist.Cards {
-		// Update progress for fetching card
-		if progress != nil {
-			progress.UpdateStage(fmt.Sprintf("Fetching card: %s", cardEntry.ID))
+// AddCard writes every face of a resolved card, once per copy
+func (g *Generator) AddCard(cardEntry model.CardEntry) error {
+	size := cardSizeFor(cardEntry.Card, g.cardSize)
+	g.cardPages = g.cardPages[:0]
+
+	var errs []error
+	faces := cardEntry.Faces
+	for i := 0; i < len(faces); i++ {
+		// Add face page for each quantity. Faces without an image are left
+		// blank (text on PDF causes font issues).
+		face := faces[i]
+		rotate := needsRotation(cardEntry.Card, face.ImagePath)
+		g.bookmark = &bookmark{section: cardEntry.Section, key: cardEntry.Section + "|" + cardEntry.ID + "|" + face.Name, title: face.Name}
+		var placed []int
+		var err error
+		if i+1 < len(faces) && faces[i+1].Back {
+			back := faces[i+1]
+			placed, err = g.writeDuplex(g.pages, cardEntry.ID, face, back, size, cardEntry.Qty,
+				rotate, needsRotation(cardEntry.Card, back.ImagePath))
+			i++
+		} else {
+			placed, err = g.writeFace(g.pages, cardEntry.ID, face, size, cardEntry.Qty, rotate)
 		}
-
-		// Fetch card data from Scryfall
-		card, err := scryfall.FindCardByID(cardEntry.ID)
+		g.bookmark = nil
+		g.cardPages = append(g.cardPages, placed...)
 		if err != nil {
-			log.Error().Err(err).Str("cardID", cardEntry.ID).Msg("Failed to fetch card data")
-			if progress != nil {
-				progress.AddError(cardEntry.ID, err.Error())
-			}
-			// Skip error cards for now - just log the error
-			continue
+			errs = append(errs, fmt.Errorf("%s: %w", face.Name, err))
 		}
+	}
+	return errors.Join(errs...)
+}
 
-		// Update card entry with fetched data
-		entryWithCard := cardEntry
-		entryWithCard.Card = card
-
-		// Handle double-sided cards
-		if len(card.CardFaces) > 0 {
-			// For double-sided cards, create separate pages for each face
-			for _, face := range card.CardFaces {
-				faceEntry := CardEntry{
-					Qty: 1, // Each face gets its own page
-					ID:  cardEntry.ID,
-					Card: scryfall.Card{
-						Name:      face.Name,
-						ImageURIs: face.ImageURIs,
-					},
-				}
-
-				// Update progress for generating face page
-				if progress != nil {
-					progress.UpdateStage(fmt.Sprintf("Generating page: %s", face.Name))
-				}
-
-				facePage, err := g.generatePage(faceEntry, cacheDir)
-				if err != nil {
-					log.Error().Err(err).Str("cardID", cardEntry.ID).Str("face", face.Name).Msg("Failed to generate face page")
-					if progress != nil {
-						progress.AddError(fmt.Sprintf("%s (%s)", face.Name, cardEntry.ID), err.Error())
-					}
-					continue
-				}
-
-				// Only add non-empty pages
-				if len(facePage) > 0 {
-					// Add face page for each quantity
-					for i := 0; i < cardEntry.Qty; i++ {
-						pages = append(pages, facePage)
-					}
-				}
-			}
-		} else {
-			// Single-sided card
-			if progress != nil {
-				progress.UpdateStage(fmt.Sprintf("Generating page: %s", card.Name))
-			}
+// addBookmark adds the pending bookmark for the face being written, which
+// points at the current page
+func (g *Generator) addBookmark(pages *pageWriter) {
+	if g.bookmark == nil || g.outline == nil {
+		return
+	}
+	g.outline.addCard(g.bookmark.section, g.bookmark.key, g.bookmark.title, pages.height)
+	g.bookmark = nil
+}
 
-			page, err := g.generatePage(entryWithCard, cacheDir)
-			if err != nil {
-				log.Error().Err(err).Str("cardID", cardEntry.ID).Msg("Failed to generate card page")
-				if progress != nil {
-					progress.AddError(card.Name, err.Error())
-				}
-				continue
-			}
+// CardPages returns the page numbers written by the most recent AddCard call
+func (g *Generator) CardPages() []int {
+	pages := slices.Clone(g.cardPages)
+	slices.Sort(pages)
+	return slices.Compact(pages)
+}
 
-			// Only add non-empty pages
-			if len(page) > 0 {
-				// Add page for each quantity
-				for i := 0; i < cardEntry.Qty; i++ {
-					pages = append(pages, page)
-				}
-			}
+// Finish writes the document to the output path
+func (g *Generator) Finish() error {
+	if g.doc == nil || g.pages.page() == 0 {
+		return fmt.Errorf("no pages generated")
+	}
+	if g.pages.duplex {
+		if err := g.pages.closeSheet(g.pages.labels[len(g.pages.labels)-1]); err != nil {
+			return err
 		}
 	}
-
-	if len(pages) == 0 {
-		return fmt.Errorf("no pages generated")
+	if len(g.textPage.Lines) > 0 {
+		if err := g.writeTextPage(); err != nil {
+			return err
+		}
 	}
 
-	// Create final PDF by combining all pages
-	if progress != nil {
-		progress.UpdateStage("Assembling PDF")
+	outline := g.outline.link()
+	if err := g.doc.WritePdf(g.outputPath); err != nil {
+		return err
 	}
 
-	size := gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}
-	pdf := gopdf.GoPdf{}
-	pdf.Start(gopdf.Config{PageSize: size})
-	for _, b := range pages {
-		if len(b) == 0 {
-			continue
+	// Add the card images and page labels once the document is on disk
+	err := appendUpdate(g.outputPath, func(update *incrementalUpdate, doc *pdfStructure) error {
+		if err := g.images.write(update); err != nil {
+			return fmt.Errorf("failed to write card images: %w", err)
 		}
-
-		reader := bytes.NewReader(b)
-		if err := pdf.ImportPagesFromSource(reader, "/MediaBox"); err != nil {
-			return err
+		if err := writeNavigation(update, doc, outline, g.pages.labels); err != nil {
+			return fmt.Errorf("failed to add PDF navigation: %w", err)
 		}
-	}
-	if err := pdf.WritePdf(g.outputPath); err != nil {
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
+	g.doc = nil
 	return nil
 }
 
-// generatePage creates a single PDF page for a card entry
-func (g *Generator) generatePage(ce CardEntry, cacheDir string) ([]byte, error) {
-	// Create PDF with dimensions including bleed
-	size := gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}
-	pdf := gopdf.GoPdf{}
-	pdf.Start(gopdf.Config{PageSize: size})
-	pdf.AddPage()
-
-	// Fill bleed area with black background (same color as card border)
-	if g.bleedAmount > 0 {
-		pdf.SetFillColor(0, 0, 0) // Black
-		pdf.RectFromUpperLeftWithStyle(0, 0, g.TotalWidth(), g.TotalHeight(), "F")
+// documentInfo builds the PDF metadata for a decklist
+func documentInfo(decklist *model.Decklist, generatedAt time.Time) gopdf.PdfInfo {
+	title := decklist.Name
+	if title == "" {
+		title = "DeckForge Proxies"
 	}
+	subject := "MTG proxy deck"
+	if decklist.Source != "" {
+		subject = fmt.Sprintf("MTG proxy deck generated from %s", filepath.Base(decklist.Source))
+	}
+	return gopdf.PdfInfo{
+		Title:        title,
+		Subject:      subject,
+		Creator:      "DeckForge CLI",
+		Producer:     "DeckForge CLI",
+		CreationDate: generatedAt,
+	}
+}
+
+// newDocument starts an empty output document sized in millimetres
+func (g *Generator) newDocument() *gopdf.GoPdf {
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{
+		Unit:     gopdf.UnitMM,
+		PageSize: gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()},
+	})
+	return doc
+}
 
-	// Determine image URL to use
-	var imageURL string
-	var imagePath string
-	var err error
-
-	if ce.Card.ImageURIs.Normal != "" {
-		imageURL = ce.Card.ImageURIs.Normal
-		// Download image from URL
-		cacheKey := fmt.Sprintf("%s_normal", ce.ID)
-		if ce.Card.Name != "" {
-			cacheKey = fmt.Sprintf("%s_%s_normal", ce.ID, sanitizeFilename(ce.Card.Name))
+// writeFace places qty copies of a single card face and returns the page
+// numbers the copies were placed on, in order and without duplicates. Copies
+// whose image cannot be embedded are still placed, and the error is returned.
+func (g *Generator) writeFace(pages *pageWriter, cardID string, face model.Face, size CardSize, qty int, rotate bool) ([]int, error) {
+	var placed []int
+	var embedErr error
+	for i := 0; i < qty; i++ {
+		x, y, err := pages.slot(size, face.Name)
+		if err != nil {
+			return placed, err
+		}
+		if page := pages.page(); len(placed) == 0 || placed[len(placed)-1] != page {
+			placed = append(placed, page)
+		}
+		g.addBookmark(pages)
+		if err := g.placeFace(pages.doc, x, y, size, face, rotate, false); err != nil && embedErr == nil {
+			log.Error().Err(err).Str("cardID", cardID).Msg("Failed to embed image")
+			embedErr = err
 		}
-		imagePath, err = scryfall.DownloadImageFromURL(imageURL, cacheKey, cacheDir)
-	} else {
-		// Fallback: try to get from Scryfall API
-		imagePath, err = scryfall.DownloadCardImage(ce.ID, cacheDir, "normal")
 	}
+	return placed, embedErr
+}
 
-	if err != nil {
-		// Log error (text on PDF causes font issues, so just log for now)
-		log.Error().Err(err).Str("cardID", ce.ID).Str("cardName", ce.Card.Name).Msg("Failed to load card image")
-
-		// Return empty page for now (avoids PDF corruption)
-		page, err := pdf.GetBytesPdfReturnErr()
+// writeDuplex places qty copies of a front face, each with the back face
+// behind it on the following page, and returns the pages used. Fronts start a
+// new sheet so duplex printing puts every back on the reverse of its front.
+// Backs are placed as backs, so they take the back calibration offsets.
+func (g *Generator) writeDuplex(pages *pageWriter, cardID string, front, back model.Face, size CardSize, qty int, rotateFront, rotateBack bool) ([]int, error) {
+	var placed []int
+	var embedErr error
+	for qty > 0 {
+		count, err := pages.startFront(size, front.Name)
 		if err != nil {
-			return nil, err
+			return placed, err
+		}
+		count = min(count, qty)
+		g.addBookmark(pages)
+		for i := 0; i < count; i++ {
+			x, y := pages.slotAt(i, false)
+			if err := g.placeFace(pages.doc, x, y, size, front, rotateFront, false); err != nil && embedErr == nil {
+				log.Error().Err(err).Str("cardID", cardID).Msg("Failed to embed image")
+				embedErr = err
+			}
+		}
+		placed = append(placed, pages.page())
+
+		if err := pages.startBack(size, back.Name); err != nil {
+			return placed, err
 		}
-		return page, nil
+		for i := 0; i < count; i++ {
+			x, y := pages.slotAt(i, true)
+			if err := g.placeFace(pages.doc, x, y, size, back, rotateBack, true); err != nil && embedErr == nil {
+				log.Error().Err(err).Str("cardID", cardID).Msg("Failed to embed image")
+				embedErr = fmt.Errorf("%s: %w", back.Name, err)
+			}
+		}
+		placed = append(placed, pages.page())
+		qty -= count
+	}
+	return placed, embedErr
+}
+
+// placeFace draws a face as text or as its card image. Back is set for the
+// back of a duplex card, which takes the back calibration offsets.
+func (g *Generator) placeFace(doc *gopdf.GoPdf, x, y float64, size CardSize, face model.Face, rotate, back bool) error {
+	if len(face.Text) > 0 {
+		return g.placeText(doc, x, y, size, face.Text, back)
+	}
+	return g.placeCard(doc, x, y, size, face.ImagePath, rotate, back)
+}
+
+// placeCard draws a card with its bleed box at x,y on the current page.
+// The card is kept (without art) when the image cannot be embedded, and an
+// image decode failure is returned.
+// Rotated images are landscape scans turned 90 degrees into the portrait trim box.
+// Printer calibration is applied to the final position and size, with the
+// back offsets added when back is set.
+func (g *Generator) placeCard(doc *gopdf.GoPdf, x, y float64, size CardSize, imagePath string, rotate, back bool) error {
+	// Fill bleed area with black background (same color as card border)
+	if g.bleedAmount > 0 {
+		boxX, boxY := g.calibration.point(x, y, back)
+		boxWidth, boxHeight := g.calibration.size(size.Width+2*g.bleedAmount, size.Height+2*g.bleedAmount)
+		doc.SetFillColor(0, 0, 0) // Black
+		doc.RectFromUpperLeftWithStyle(boxX, boxY, boxWidth, boxHeight, "F")
+	}
+
+	if imagePath == "" {
+		return nil
 	}
 
 	// Embed the image at bleed offset, scaling to fit card dimensions
 	imageX, imageY := g.ImagePosition()
-	err = pdf.Image(imagePath, imageX, imageY, &gopdf.Rect{W: CardWidth, H: CardHeight})
-	if err != nil {
-		log.Warn().Err(err).Str("cardID", ce.ID).Msg("Failed to embed image")
-		// Return empty page
-		page, err := pdf.GetBytesPdfReturnErr()
-		if err != nil {
-			return nil, err
-		}
-		return page, nil
+	imageX, imageY = g.calibration.point(x+imageX, y+imageY, back)
+	width, height := g.calibration.size(size.Width, size.Height)
+	if rotate {
+		// Draw the landscape image centered on the card, then turn it upright
+		centerX := imageX + width/2
+		centerY := imageY + height/2
+		doc.Rotate(90, centerX, centerY)
+		defer doc.RotateReset()
+		return embedError(g.images.embed(doc, imagePath, centerX-height/2, centerY-width/2, &gopdf.Rect{W: height, H: width}))
 	}
+	return embedError(g.images.embed(doc, imagePath, imageX, imageY, &gopdf.Rect{W: width, H: height}))
+}
 
-	page, err := pdf.GetBytesPdfReturnErr()
-	if err != nil {
-		return nil, err
+// embedError tags a failure to embed a card image as an image decode failure
+func embedError(err error) error {
+	if err == nil {
+		return nil
 	}
-	return page, nil
+	return failure.New(failure.ImageDecode, fmt.Errorf("failed to embed image: %w", err))
 }
 
 // sanitizeFilename creates a safe filename from card name
@@ -272,14 +379,3 @@ func sanitizeFilename(name string) string {
 	// Simple sanitization - replace spaces and special chars
 	return strings.ReplaceAll(strings.ReplaceAll(name, " ", "_"), "/", "_")
 }
-
-// calculateTotalOperations calculates the total number of operations for unified progress tracking
-func calculateTotalOperations(decklist *Decklist) int {
-	total := 0
-	for range decklist.Cards {
-		total += 1 // Card data fetch
-		total += 1 // Page generation
-	}
-	total += 1 // Assembling PDF
-	return total
-}
@@ -0,0 +1,350 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/signintech/gopdf"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestImage creates a noisy card-sized JPEG so embedded sizes are realistic
+func writeTestImage(t testing.TB, dir, name string) string {
+	return writeTestImageSize(t, dir, name, 488, 680)
+}
+
+// writeTestImageSize creates a noisy JPEG with the given pixel dimensions
+func writeTestImageSize(t testing.TB, dir, name string, width, height int) string {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	rng := rand.New(rand.NewSource(int64(len(name))))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255})
+		}
+	}
+
+	path := filepath.Join(dir, name+".jpg")
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+	require.NoError(t, jpeg.Encode(out, img, &jpeg.Options{Quality: 85}))
+	return path
+}
+
+// testDeck builds resolved cards backed by local images, four copies each
+func testDeck(t testing.TB, unique int) []model.CardEntry {
+	dir := t.TempDir()
+	cards := make([]model.CardEntry, 0, unique)
+	for i := 0; i < unique; i++ {
+		name := fmt.Sprintf("card-%d", i)
+		cards = append(cards, model.CardEntry{
+			Qty:   4,
+			ID:    name,
+			Faces: []model.Face{{Name: name, ImagePath: writeTestImage(t, dir, name)}},
+		})
+	}
+	return cards
+}
+
+// assembleStreaming writes resolved cards the way the render driver does
+func assembleStreaming(g *Generator, cards []model.CardEntry, outputPath string) error {
+	g.SetOutputPath(outputPath)
+	if err := g.Start(&model.Decklist{Name: "Benchmark"}); err != nil {
+		return err
+	}
+	for _, card := range cards {
+		if err := g.AddCard(card); err != nil {
+			return err
+		}
+	}
+	return g.Finish()
+}
+
+// assembleBuffered is the previous approach: one PDF per card held in memory,
+// then every buffered page imported into the final document
+func assembleBuffered(g *Generator, cards []model.CardEntry, outputPath string) error {
+	size := gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}
+	pages := [][]byte{}
+	for _, card := range cards {
+		for _, face := range card.Faces {
+			page := gopdf.GoPdf{}
+			page.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: size})
+			page.AddPage()
+			x, y := g.ImagePosition()
+			if err := page.Image(face.ImagePath, x, y, &gopdf.Rect{W: g.cardSize.Width, H: g.cardSize.Height}); err != nil {
+				return err
+			}
+			b, err := page.GetBytesPdfReturnErr()
+			if err != nil {
+				return err
+			}
+			for i := 0; i < card.Qty; i++ {
+				pages = append(pages, b)
+			}
+		}
+	}
+
+	doc := gopdf.GoPdf{}
+	doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: size})
+	for _, b := range pages {
+		if err := doc.ImportPagesFromSource(bytes.NewReader(b), "/MediaBox"); err != nil {
+			return err
+		}
+	}
+	return doc.WritePdf(outputPath)
+}
+
+func TestAssembleStreaming(t *testing.T) {
+	t.Run("writes one page per copy", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		cards := testDeck(t, 3)
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+
+		require.NoError(t, assembleStreaming(g, cards, outputPath))
+
+		doc := gopdf.GoPdf{}
+		doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}})
+		require.NoError(t, doc.ImportPagesFromSource(outputPath, "/MediaBox"))
+		require.Equal(t, 12, doc.GetNumberOfPages())
+	})
+
+	t.Run("copies share one embedded image", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		cards := testDeck(t, 1)
+		dir := t.TempDir()
+
+		single := filepath.Join(dir, "single.pdf")
+		cards[0].Qty = 1
+		require.NoError(t, assembleStreaming(g, cards, single))
+
+		many := filepath.Join(dir, "many.pdf")
+		cards[0].Qty = 20
+		require.NoError(t, assembleStreaming(g, cards, many))
+
+		singleInfo, err := os.Stat(single)
+		require.NoError(t, err)
+		manyInfo, err := os.Stat(many)
+		require.NoError(t, err)
+		require.Less(t, manyInfo.Size(), 2*singleInfo.Size())
+	})
+
+	t.Run("images that cannot be embedded are returned as decode failures", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		dir := t.TempDir()
+		corrupt := filepath.Join(dir, "corrupt.jpg")
+		require.NoError(t, os.WriteFile(corrupt, []byte("not a jpeg"), 0644))
+		g.SetOutputPath(filepath.Join(dir, "deck.pdf"))
+		require.NoError(t, g.Start(&model.Decklist{}))
+
+		err := g.AddCard(model.CardEntry{Qty: 2, ID: "corrupt", Faces: []model.Face{{Name: "Corrupt", ImagePath: corrupt}}})
+		require.Error(t, err)
+		require.Equal(t, failure.ImageDecode, failure.KindOf(err))
+		require.Equal(t, []int{1, 2}, g.CardPages(), "copies keep their place without art")
+		require.NoError(t, g.Finish())
+	})
+
+	t.Run("records the pages of each card", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		g.SetOutputPath(filepath.Join(t.TempDir(), "deck.pdf"))
+		require.NoError(t, g.Start(&model.Decklist{}))
+
+		cards := testDeck(t, 2)
+		require.NoError(t, g.AddCard(cards[0]))
+		require.Equal(t, []int{1, 2, 3, 4}, g.CardPages())
+		require.NoError(t, g.AddCard(cards[1]))
+		require.Equal(t, []int{5, 6, 7, 8}, g.CardPages())
+
+		// Nine cards fit on a letter sheet without bleed
+		g.SetPageSize(PageSizeLetter)
+		g.bleedAmount = 0
+		require.NoError(t, g.Start(&model.Decklist{}))
+		require.NoError(t, g.AddCard(cards[0]))
+		require.NoError(t, g.AddCard(cards[1]))
+		require.Equal(t, []int{1}, g.CardPages())
+	})
+
+	t.Run("back faces follow their front for duplex printing", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		g.SetOutputPath(filepath.Join(t.TempDir(), "deck.pdf"))
+
+		cards := testDeck(t, 2)
+		cards[0].Qty = 1
+		meld := cards[1]
+		meld.Qty = 2
+		meld.Faces = append(meld.Faces, model.Face{Name: "Back", ImagePath: meld.Faces[0].ImagePath, Back: true})
+		require.NoError(t, g.Start(&model.Decklist{Cards: []model.CardEntry{cards[0], meld}}))
+
+		require.NoError(t, g.AddCard(cards[0]))
+		require.NoError(t, g.AddCard(meld))
+		require.Equal(t, []int{3, 4, 5, 6}, g.CardPages())
+		require.Equal(t, 6, g.doc.GetNumberOfPages())
+	})
+
+	t.Run("duplex decks give single-faced cards blank backs", func(t *testing.T) {
+		cards := testDeck(t, 3)
+		cards[0].Qty = 1
+		cards[1].Qty = 1
+		cards[1].Faces = append(cards[1].Faces, model.Face{Name: "Back", ImagePath: cards[1].Faces[0].ImagePath, Back: true})
+		cards[2].Qty = 2
+		decklist := &model.Decklist{Cards: cards}
+
+		for _, tc := range []struct {
+			name     string
+			pageSize PageSize
+			pages    [][]int
+			total    int
+		}{
+			// Single1, blank, Front, Back, Single2, blank, Single2, blank
+			{"card pages", PageSize{}, [][]int{{1}, {3, 4}, {5, 7}}, 8},
+			// Single1 | blank, Front | Back, Single2 x2 | blank
+			{"sheets", PageSizeA4, [][]int{{1}, {3, 4}, {5}}, 6},
+		} {
+			g := NewGenerator(3.0).(*Generator)
+			g.SetPageSize(tc.pageSize)
+			g.SetOutputPath(filepath.Join(t.TempDir(), "deck.pdf"))
+			require.NoError(t, g.Start(decklist))
+			for i, card := range cards {
+				require.NoError(t, g.AddCard(card))
+				require.Equal(t, tc.pages[i], g.CardPages(), "%s: %s", tc.name, card.ID)
+			}
+			for _, pages := range tc.pages {
+				require.Equal(t, 1, pages[0]%2, "%s: fronts are on odd pages", tc.name)
+			}
+			require.NoError(t, g.Finish())
+			require.Equal(t, tc.total, len(g.pages.labels), tc.name)
+		}
+	})
+
+	t.Run("text faces are printed as checklist cards", func(t *testing.T) {
+		g := NewGenerator(0).(*Generator)
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		cards := testDeck(t, 1)
+		cards[0].Qty = 2
+		cards[0].Faces = append(cards[0].Faces, model.Face{Name: "Checklist", Text: []string{"CHECKLIST", "", "Delver of Secrets  {U}"}})
+		require.NoError(t, assembleStreaming(g, cards, outputPath))
+
+		doc := gopdf.GoPdf{}
+		doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}})
+		require.NoError(t, doc.ImportPagesFromSource(outputPath, "/MediaBox"))
+		require.Equal(t, 4, doc.GetNumberOfPages())
+	})
+
+	t.Run("missing image still produces a page", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		doc := g.newDocument()
+		pages := newPageWriter(doc, g.pageSize, g.bleedAmount)
+		placed, err := g.writeFace(pages, "missing", model.Face{Name: "Missing"}, g.cardSize, 2, false)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, placed)
+		require.Equal(t, 2, doc.GetNumberOfPages())
+	})
+}
+
+func TestGeneratorFinish(t *testing.T) {
+	t.Run("finish without cards", func(t *testing.T) {
+		g := NewGenerator(3.0)
+		g.SetOutputPath(filepath.Join(t.TempDir(), "empty.pdf"))
+		require.NoError(t, g.Start(&model.Decklist{}))
+		require.EqualError(t, g.Finish(), "no pages generated")
+	})
+
+	t.Run("finish without start", func(t *testing.T) {
+		require.EqualError(t, NewGenerator(3.0).Finish(), "no pages generated")
+	})
+}
+
+// heapPeak is the largest heap seen while assembling a deck
+type heapPeak struct {
+	total uint64 // Allocated heap, including garbage not yet collected
+	live  uint64 // Heap still reachable at a garbage collection
+}
+
+// sampleHeap reads the allocated heap and the live heap marked by the last GC
+func sampleHeap() heapPeak {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	live := []metrics.Sample{{Name: "/gc/heap/live:bytes"}}
+	metrics.Read(live)
+	return heapPeak{total: stats.HeapAlloc, live: live[0].Value.Uint64()}
+}
+
+// max combines two peaks
+func (p heapPeak) max(other heapPeak) heapPeak {
+	return heapPeak{total: max(p.total, other.total), live: max(p.live, other.live)}
+}
+
+// peakHeap runs fn while sampling the heap and returns the largest samples in bytes
+func peakHeap(fn func() error) (heapPeak, error) {
+	runtime.GC()
+	peak := sampleHeap()
+
+	done := make(chan struct{})
+	sampled := make(chan heapPeak)
+	go func() {
+		var peak heapPeak
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			peak = peak.max(sampleHeap())
+			select {
+			case <-done:
+				sampled <- peak
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	err := fn()
+	close(done)
+	// Collect so that the live heap at the end of the run is counted too
+	runtime.GC()
+	return peak.max(<-sampled).max(sampleHeap()), err
+}
+
+// benchmarkAssemble reports time, allocations and the peak heap of assembling
+// decks of increasing size, to show how memory scales with unique cards.
+// peak-MB includes garbage waiting for collection; live-MB is what the
+// assembly actually retains.
+func benchmarkAssemble(b *testing.B, assemble func(*Generator, []model.CardEntry, string) error, sizes ...int) {
+	for _, unique := range sizes {
+		b.Run(fmt.Sprintf("unique=%d", unique), func(b *testing.B) {
+			g := NewGenerator(3.0).(*Generator)
+			cards := testDeck(b, unique)
+			outputPath := filepath.Join(b.TempDir(), "deck.pdf")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			var peak heapPeak
+			for i := 0; i < b.N; i++ {
+				runPeak, err := peakHeap(func() error { return assemble(g, cards, outputPath) })
+				if err != nil {
+					b.Fatal(err)
+				}
+				peak = peak.max(runPeak)
+			}
+			b.ReportMetric(float64(peak.total)/(1<<20), "peak-MB")
+			b.ReportMetric(float64(peak.live)/(1<<20), "live-MB")
+		})
+	}
+}
+
+func BenchmarkAssembleBuffered(b *testing.B) {
+	benchmarkAssemble(b, assembleBuffered, 15, 30, 60)
+}
+
+func BenchmarkAssembleStreaming(b *testing.B) {
+	// 540 unique cards is a full cube
+	benchmarkAssemble(b, assembleStreaming, 15, 30, 60, 540)
+}
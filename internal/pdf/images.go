@@ -0,0 +1,332 @@
+package pdf
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Image export defaults
+const (
+	DefaultDPI         = 300
+	DefaultImageFormat = "png"
+)
+
+// ManifestFile is the name of the quantity manifest written by ImageExporter
+const ManifestFile = "manifest.json"
+
+// ImageExporter writes one image per card face at an exact pixel size for
+// online card printers, with fronts and backs in separate folders. The
+// folders can be written to disk or packed into a ZIP archive.
+type ImageExporter struct {
+	bleedAmount float64
+	outputPath  string
+	dpi         int
+	format      string
+	cardSize    CardSize
+	archive     bool
+
+	// Export state between Start and Finish
+	sink     imageSink
+	manifest Manifest
+	index    int
+}
+
+// ManifestEntry lists the files and quantity for one card in the export
+type ManifestEntry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Section  string `json:"section,omitempty"`
+	Set      string `json:"set,omitempty"`
+	Finish   string `json:"finish,omitempty"`
+	Quantity int    `json:"quantity"`
+	Front    string `json:"front"`
+	Back     string `json:"back,omitempty"` // Empty when the printer's default card back is used
+}
+
+// Manifest describes an image export
+type Manifest struct {
+	DPI    int             `json:"dpi"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Bleed  float64         `json:"bleed_mm"`
+	Format string          `json:"format"`
+	Cards  []ManifestEntry `json:"cards"`
+}
+
+// NewImageExporter creates an image folder exporter with the specified bleed amount
+func NewImageExporter(bleedAmount float64) *ImageExporter {
+	return &ImageExporter{
+		bleedAmount: bleedAmount,
+		dpi:         DefaultDPI,
+		format:      DefaultImageFormat,
+		cardSize:    CardSizeStandard,
+	}
+}
+
+// NewZipExporter creates an exporter that writes the image folders and
+// manifest into a single ZIP archive
+func NewZipExporter(bleedAmount float64) *ImageExporter {
+	e := NewImageExporter(bleedAmount)
+	e.archive = true
+	return e
+}
+
+// SetOutputPath sets the directory (or ZIP file) the images and manifest are written to
+func (e *ImageExporter) SetOutputPath(path string) {
+	e.outputPath = path
+}
+
+// SetCardSize sets the card size used for regular cards
+func (e *ImageExporter) SetCardSize(size CardSize) {
+	e.cardSize = size
+}
+
+// SetDPI sets the output resolution
+func (e *ImageExporter) SetDPI(dpi int) error {
+	if dpi <= 0 {
+		return fmt.Errorf("DPI must be positive, got %d", dpi)
+	}
+	e.dpi = dpi
+	return nil
+}
+
+// SetFormat sets the image file format: png or jpeg
+func (e *ImageExporter) SetFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "png":
+		e.format = "png"
+	case "jpg", "jpeg":
+		e.format = "jpg"
+	default:
+		return fmt.Errorf("unsupported image format '%s'", format)
+	}
+	return nil
+}
+
+// PixelSize returns the output image dimensions for a card size, including bleed
+func (e *ImageExporter) PixelSize(size CardSize) (int, int) {
+	return mmToPixels(size.Width+2*e.bleedAmount, e.dpi), mmToPixels(size.Height+2*e.bleedAmount, e.dpi)
+}
+
+// ImageQuality returns the Scryfall image quality to download: the lossless
+// PNG scan for PNG output, otherwise the smallest image at least as wide as a
+// card at the export DPI
+func (e *ImageExporter) ImageQuality() string {
+	if e.format == "png" {
+		return scryfall.QualityPNG
+	}
+	width := mmToPixels(e.cardSize.Width, e.dpi)
+	for _, quality := range []string{scryfall.QualityNormal, scryfall.QualityLarge} {
+		if scryfall.ImageWidths[quality] >= width {
+			return quality
+		}
+	}
+	return scryfall.QualityPNG
+}
+
+// Start prepares the output folders or archive
+func (e *ImageExporter) Start(decklist *model.Decklist) error {
+	sink, err := e.openSink()
+	if err != nil {
+		return err
+	}
+	e.sink = sink
+	e.index = 0
+
+	width, height := e.PixelSize(e.cardSize)
+	e.manifest = Manifest{DPI: e.dpi, Width: width, Height: height, Bleed: e.bleedAmount, Format: e.format}
+	return nil
+}
+
+// AddCard renders the faces of a card. A face printed on the back of the
+// preceding face is written as its back; every other face is a front of its
+// own, with its own manifest entry.
+func (e *ImageExporter) AddCard(cardEntry model.CardEntry) error {
+	size := cardSizeFor(cardEntry.Card, e.cardSize)
+
+	var entries []ManifestEntry
+	var errs []error
+	for _, face := range cardEntry.Faces {
+		side := "back"
+		if !face.Back || len(entries) == 0 {
+			// The first front is named after the card, later ones after their face
+			e.index++
+			side = "front"
+			name := cardEntry.Card.Name
+			if len(entries) > 0 {
+				name = face.Name
+			}
+			entries = append(entries, ManifestEntry{
+				ID:       cardEntry.ID,
+				Name:     name,
+				Section:  cardEntry.Section,
+				Set:      cardEntry.Card.Set,
+				Finish:   cardEntry.Finish,
+				Quantity: cardEntry.Qty,
+			})
+		}
+		entry := &entries[len(entries)-1]
+		file := path.Join(side, fmt.Sprintf("%03d_%s.%s", e.index, sanitizeFilename(entry.Name), e.format))
+		if err := e.writeFace(face, needsRotation(cardEntry.Card, face.ImagePath), size, file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", face.Name, err))
+			continue
+		}
+		if side == "front" {
+			entry.Front = file
+		} else {
+			entry.Back = file
+		}
+	}
+	for _, entry := range entries {
+		if entry.Front != "" {
+			e.manifest.Cards = append(e.manifest.Cards, entry)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Finish writes the manifest and closes the output
+func (e *ImageExporter) Finish() error {
+	if e.sink == nil {
+		return fmt.Errorf("no images generated")
+	}
+	defer func() { e.sink = nil }()
+
+	if len(e.manifest.Cards) == 0 {
+		e.sink.Close()
+		return fmt.Errorf("no images generated")
+	}
+
+	data, err := sonic.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		e.sink.Close()
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	out, err := e.sink.Create(ManifestFile)
+	if err != nil {
+		e.sink.Close()
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	_, err = out.Write(data)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		e.sink.Close()
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return e.sink.Close()
+}
+
+// writeFace renders a single face at the export size and saves it
+func (e *ImageExporter) writeFace(face model.Face, rotate bool, size CardSize, name string) error {
+	var src image.Image
+	if face.ImagePath != "" {
+		file, err := os.Open(face.ImagePath)
+		if err != nil {
+			return fmt.Errorf("failed to open image: %w", err)
+		}
+		src, _, err = image.Decode(file)
+		file.Close()
+		if err != nil {
+			return failure.New(failure.ImageDecode, fmt.Errorf("failed to decode image: %w", err))
+		}
+	}
+
+	width, height := e.PixelSize(size)
+	img := renderFaceImage(src, width, height, mmToPixels(e.bleedAmount, e.dpi), rotate)
+
+	out, err := e.sink.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create image file: %w", err)
+	}
+	if e.format == "jpg" {
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: 95})
+	} else {
+		err = png.Encode(out, img)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// imageSink stores exported files by slash-separated name
+type imageSink interface {
+	Create(name string) (io.WriteCloser, error)
+	Close() error
+}
+
+// openSink creates the folder or archive the export is written to
+func (e *ImageExporter) openSink() (imageSink, error) {
+	if e.archive {
+		file, err := os.Create(e.outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create archive: %w", err)
+		}
+		return &zipSink{file: file, writer: zip.NewWriter(file)}, nil
+	}
+
+	for _, dir := range []string{"front", "back"} {
+		if err := os.MkdirAll(filepath.Join(e.outputPath, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	return dirSink(e.outputPath), nil
+}
+
+// dirSink writes files below a directory
+type dirSink string
+
+func (d dirSink) Create(name string) (io.WriteCloser, error) {
+	return os.Create(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+func (d dirSink) Close() error {
+	return nil
+}
+
+// zipSink writes files into a ZIP archive, one at a time
+type zipSink struct {
+	file   *os.File
+	writer *zip.Writer
+}
+
+func (z *zipSink) Create(name string) (io.WriteCloser, error) {
+	w, err := z.writer.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return nopWriteCloser{w}, nil
+}
+
+func (z *zipSink) Close() error {
+	err := z.writer.Close()
+	if closeErr := z.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// nopWriteCloser adapts a zip entry writer, which is closed by the archive
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
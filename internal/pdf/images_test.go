@@ -0,0 +1,213 @@
+package pdf
+
+import (
+	"archive/zip"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageExporterSettings(t *testing.T) {
+	t.Run("pixel size includes bleed", func(t *testing.T) {
+		e := NewImageExporter(3.0)
+		width, height := e.PixelSize(CardSizeStandard)
+		require.Equal(t, 815, width)
+		require.Equal(t, 1110, height)
+
+		require.NoError(t, e.SetDPI(600))
+		width, height = e.PixelSize(CardSizeStandard)
+		require.Equal(t, 1630, width)
+		require.Equal(t, 2220, height)
+	})
+
+	t.Run("invalid DPI", func(t *testing.T) {
+		require.Error(t, NewImageExporter(0).SetDPI(0))
+	})
+
+	t.Run("image formats", func(t *testing.T) {
+		e := NewImageExporter(0)
+		require.NoError(t, e.SetFormat("JPEG"))
+		require.Equal(t, "jpg", e.format)
+		require.NoError(t, e.SetFormat("png"))
+		require.Equal(t, "png", e.format)
+		require.Error(t, e.SetFormat("gif"))
+	})
+
+	t.Run("source images cover the export DPI", func(t *testing.T) {
+		e := NewImageExporter(3.0)
+		require.Equal(t, scryfall.QualityPNG, e.ImageQuality(), "PNG exports use lossless scans")
+
+		require.NoError(t, e.SetFormat("jpg"))
+		for _, dpi := range []int{150, 200, 300, 600} {
+			require.NoError(t, e.SetDPI(dpi))
+			quality := e.ImageQuality()
+			cardWidth := mmToPixels(CardSizeStandard.Width, dpi)
+			if dpi <= 200 {
+				require.GreaterOrEqual(t, scryfall.ImageWidths[quality], cardWidth, "%d DPI", dpi)
+			} else {
+				require.Equal(t, scryfall.QualityPNG, quality, "%d DPI uses the largest scan", dpi)
+			}
+		}
+
+		require.NoError(t, e.SetDPI(150))
+		require.Equal(t, scryfall.QualityNormal, e.ImageQuality())
+		require.NoError(t, e.SetDPI(200))
+		require.Equal(t, scryfall.QualityLarge, e.ImageQuality())
+	})
+}
+
+func TestRenderFaceImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 40; x++ {
+			src.Set(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+
+	t.Run("art is scaled inside a black bleed", func(t *testing.T) {
+		img := renderFaceImage(src, 100, 140, 10, false)
+		require.Equal(t, image.Rect(0, 0, 100, 140), img.Bounds())
+		require.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(5, 5))
+		require.Equal(t, color.RGBA{255, 255, 255, 255}, img.RGBAAt(50, 70))
+	})
+
+	t.Run("transparent corners show the bleed", func(t *testing.T) {
+		rounded := image.NewRGBA(image.Rect(0, 0, 40, 60))
+		draw.Draw(rounded, rounded.Bounds(), src, image.Point{}, draw.Src)
+		rounded.Set(0, 0, color.RGBA{})
+		img := renderFaceImage(rounded, 40, 60, 0, false)
+		require.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(0, 0))
+		require.Equal(t, color.RGBA{255, 255, 255, 255}, img.RGBAAt(20, 30))
+	})
+
+	t.Run("missing art leaves a black card", func(t *testing.T) {
+		img := renderFaceImage(nil, 100, 140, 10, false)
+		require.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(50, 70))
+	})
+
+	t.Run("rotation turns landscape art upright", func(t *testing.T) {
+		landscape := image.NewRGBA(image.Rect(0, 0, 60, 40))
+		landscape.Set(59, 0, color.RGBA{255, 0, 0, 255})
+		rotated := rotateImage90(landscape)
+		require.Equal(t, image.Rect(0, 0, 40, 60), rotated.Bounds())
+		require.Equal(t, color.RGBA{255, 0, 0, 255}, rotated.RGBAAt(0, 0))
+	})
+}
+
+func TestImageExporter(t *testing.T) {
+	dir := t.TempDir()
+	card := model.CardEntry{
+		Qty:    3,
+		ID:     "dfc",
+		Card:   scryfall.Card{Name: "Delver of Secrets // Insectile Aberration", Set: "isd"},
+		Finish: model.FinishFoil,
+		Faces: []model.Face{
+			{Name: "Delver of Secrets", ImagePath: writeTestImage(t, dir, "front")},
+			{Name: "Insectile Aberration", ImagePath: writeTestImage(t, dir, "back"), Back: true},
+		},
+	}
+
+	t.Run("folder export", func(t *testing.T) {
+		outputDir := filepath.Join(t.TempDir(), "deck")
+		e := NewImageExporter(3.0)
+		require.NoError(t, e.SetDPI(100))
+		e.SetOutputPath(outputDir)
+
+		require.NoError(t, e.Start(&model.Decklist{}))
+		require.NoError(t, e.AddCard(card))
+		require.NoError(t, e.Finish())
+
+		file, err := os.Open(filepath.Join(outputDir, "front", "001_"+sanitizeFilename(card.Card.Name)+".png"))
+		require.NoError(t, err)
+		defer file.Close()
+		img, err := png.Decode(file)
+		require.NoError(t, err)
+		width, height := e.PixelSize(CardSizeStandard)
+		require.Equal(t, width, img.Bounds().Dx())
+		require.Equal(t, height, img.Bounds().Dy())
+
+		data, err := os.ReadFile(filepath.Join(outputDir, ManifestFile))
+		require.NoError(t, err)
+		var manifest Manifest
+		require.NoError(t, sonic.Unmarshal(data, &manifest))
+		require.Len(t, manifest.Cards, 1)
+		require.Equal(t, 3, manifest.Cards[0].Quantity)
+		require.Equal(t, "isd", manifest.Cards[0].Set)
+		require.Equal(t, model.FinishFoil, manifest.Cards[0].Finish)
+		require.Contains(t, manifest.Cards[0].Front, "front/")
+		require.Contains(t, manifest.Cards[0].Back, "back/")
+	})
+
+	t.Run("faces not printed on the back are fronts of their own", func(t *testing.T) {
+		outputDir := filepath.Join(t.TempDir(), "deck")
+		e := NewImageExporter(0)
+		require.NoError(t, e.SetDPI(50))
+		e.SetOutputPath(outputDir)
+
+		separate := card
+		separate.Faces = []model.Face{card.Faces[0], card.Faces[1], card.Faces[0]}
+		separate.Faces[1].Back = false
+		separate.Faces[2].Name = "Extra Face"
+		duplex := card
+		duplex.Faces = []model.Face{card.Faces[0], card.Faces[1], {Name: "Third Face", ImagePath: card.Faces[0].ImagePath}}
+
+		require.NoError(t, e.Start(&model.Decklist{}))
+		require.NoError(t, e.AddCard(separate))
+		require.NoError(t, e.AddCard(duplex))
+		require.NoError(t, e.Finish())
+
+		var files []string
+		for _, entry := range e.manifest.Cards {
+			files = append(files, entry.Name+": "+entry.Front+" "+entry.Back)
+		}
+		cardFile := sanitizeFilename(card.Card.Name) + ".png"
+		require.Equal(t, []string{
+			card.Card.Name + ": front/001_" + cardFile + " ",
+			"Insectile Aberration: front/002_Insectile_Aberration.png ",
+			"Extra Face: front/003_Extra_Face.png ",
+			card.Card.Name + ": front/004_" + cardFile + " back/004_" + cardFile,
+			"Third Face: front/005_Third_Face.png ",
+		}, files)
+		for _, entry := range e.manifest.Cards {
+			require.FileExists(t, filepath.Join(outputDir, entry.Front))
+		}
+	})
+
+	t.Run("zip export", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "deck.zip")
+		e := NewZipExporter(0)
+		require.NoError(t, e.SetDPI(50))
+		require.NoError(t, e.SetFormat("jpeg"))
+		e.SetOutputPath(outputPath)
+
+		require.NoError(t, e.Start(&model.Decklist{}))
+		require.NoError(t, e.AddCard(card))
+		require.NoError(t, e.Finish())
+
+		archive, err := zip.OpenReader(outputPath)
+		require.NoError(t, err)
+		defer archive.Close()
+		var names []string
+		for _, f := range archive.File {
+			names = append(names, f.Name)
+		}
+		require.Len(t, names, 3)
+		require.Contains(t, names, ManifestFile)
+	})
+
+	t.Run("no cards", func(t *testing.T) {
+		e := NewImageExporter(0)
+		e.SetOutputPath(filepath.Join(t.TempDir(), "empty"))
+		require.NoError(t, e.Start(&model.Decklist{}))
+		require.EqualError(t, e.Finish(), "no images generated")
+	})
+}
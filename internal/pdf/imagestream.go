@@ -0,0 +1,208 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/signintech/gopdf"
+)
+
+// imageStream keeps card images out of memory while a document is built.
+// gopdf holds every embedded image until the file is written, so each unique
+// image is embedded as a one-pixel placeholder and the real image is streamed
+// from disk into the finished file, replacing the placeholder object.
+type imageStream struct {
+	images map[string]streamedImage // By image path
+	order  []string
+}
+
+// streamedImage is a card image waiting to replace its placeholder
+type streamedImage struct {
+	id     int // Object ID of the placeholder
+	format string
+	config image.Config
+}
+
+// placeholder is a one-pixel image standing in for a card image. Its ID is
+// the card image path, so copies of a card share one image object.
+type placeholder struct {
+	path string
+	*bytes.Reader
+}
+
+// ID identifies the card image for gopdf's image deduplication
+func (p placeholder) ID() string {
+	return p.path
+}
+
+// placeholderJPEG encodes the placeholder image once
+var placeholderJPEG = sync.OnceValues(func() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)), nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+})
+
+// embed draws the image at path into rect. Only the image header is read, so
+// an unreadable or unsupported image is reported for the card being placed.
+func (s *imageStream) embed(doc *gopdf.GoPdf, path string, x, y float64, rect *gopdf.Rect) error {
+	if s.images == nil {
+		s.images = make(map[string]streamedImage)
+	}
+	img, seen := s.images[path]
+	if !seen {
+		config, format, err := decodeConfig(path)
+		if err != nil {
+			return err
+		}
+		if format != "jpeg" && format != "png" {
+			return fmt.Errorf("unsupported image format %q", format)
+		}
+		// gopdf adds a new image as the next object
+		img = streamedImage{id: doc.GetNextObjectID(), format: format, config: config}
+	}
+
+	data, err := placeholderJPEG()
+	if err != nil {
+		return err
+	}
+	if err := doc.ImageByHolder(placeholder{path: path, Reader: bytes.NewReader(data)}, x, y, rect); err != nil {
+		return err
+	}
+	if !seen {
+		s.images[path] = img
+		s.order = append(s.order, path)
+	}
+	return nil
+}
+
+// decodeConfig reads the dimensions and format of the image at path
+func decodeConfig(path string) (image.Config, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, "", err
+	}
+	defer file.Close()
+	return image.DecodeConfig(bufio.NewReader(file))
+}
+
+// write replaces every placeholder with its image, one image at a time
+func (s *imageStream) write(update *incrementalUpdate) error {
+	for _, path := range s.order {
+		img := s.images[path]
+		var err error
+		if img.format == "jpeg" {
+			err = writeJPEG(update, img, path)
+		} else {
+			err = writePNG(update, img, path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// writeJPEG copies a JPEG file into an image object as is
+func writeJPEG(update *incrementalUpdate, img streamedImage, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	dict := imageDict(img.config, "/DCTDecode")
+	switch img.config.ColorModel {
+	case color.GrayModel:
+		dict = dict.set("/ColorSpace", "/DeviceGray")
+	case color.CMYKModel:
+		// Adobe CMYK JPEGs are stored inverted
+		dict = dict.set("/ColorSpace", "/DeviceCMYK").set("/Decode", "[1 0 1 0 1 0 1 0]")
+	}
+	return update.writeStream(img.id, dict, func(w io.Writer) error {
+		_, err := io.Copy(w, file)
+		return err
+	})
+}
+
+// writePNG decodes a PNG into a compressed RGB image object, with its alpha
+// channel as a soft mask when the image has transparency
+func writePNG(update *incrementalUpdate, img streamedImage, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	decoded, _, err := image.Decode(bufio.NewReader(file))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	dict := imageDict(img.config, "/FlateDecode")
+	opaque, ok := decoded.(interface{ Opaque() bool })
+	if !ok || !opaque.Opaque() {
+		mask := update.allocate()
+		maskDict := imageDict(img.config, "/FlateDecode").set("/ColorSpace", "/DeviceGray")
+		if err := update.writeStream(mask, maskDict, func(w io.Writer) error {
+			return writePixels(w, decoded, 1, func(dst []byte, c color.NRGBA) {
+				dst[0] = c.A
+			})
+		}); err != nil {
+			return err
+		}
+		dict = dict.set("/SMask", fmt.Sprintf("%d 0 R", mask))
+	}
+	return update.writeStream(img.id, dict, func(w io.Writer) error {
+		return writePixels(w, decoded, 3, func(dst []byte, c color.NRGBA) {
+			dst[0], dst[1], dst[2] = c.R, c.G, c.B
+		})
+	})
+}
+
+// imageDict describes an 8-bit RGB image object
+func imageDict(config image.Config, filter string) pdfDict {
+	return pdfDict{
+		{Key: "/Type", Value: "/XObject"},
+		{Key: "/Subtype", Value: "/Image"},
+		{Key: "/Width", Value: strconv.Itoa(config.Width)},
+		{Key: "/Height", Value: strconv.Itoa(config.Height)},
+		{Key: "/ColorSpace", Value: "/DeviceRGB"},
+		{Key: "/BitsPerComponent", Value: "8"},
+		{Key: "/Filter", Value: filter},
+	}
+}
+
+// writePixels compresses an image row by row, with each pixel written as
+// size bytes by pixel
+func writePixels(w io.Writer, img image.Image, size int, pixel func(dst []byte, c color.NRGBA)) error {
+	zw := zlib.NewWriter(w)
+	bounds := img.Bounds()
+	row := make([]byte, bounds.Dx()*size)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel(row[(x-bounds.Min.X)*size:], nrgbaAt(img, x, y))
+		}
+		if _, err := zw.Write(row); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// nrgbaAt returns a pixel without premultiplied alpha
+func nrgbaAt(img image.Image, x, y int) color.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba.NRGBAAt(x, y)
+	}
+	return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+}
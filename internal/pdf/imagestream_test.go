@@ -0,0 +1,142 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPNG creates a PNG whose top row is transparent when alpha is set
+func writeTestPNG(t testing.TB, dir, name string, alpha bool) string {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			a := uint8(255)
+			if alpha && y == 0 {
+				a = 0
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 60), G: uint8(y * 100), B: 7, A: a})
+		}
+	}
+
+	path := filepath.Join(dir, name+".png")
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+	require.NoError(t, png.Encode(out, img))
+	return path
+}
+
+// streamData returns the decompressed data of each stream in a PDF fragment
+func streamData(t *testing.T, pdf []byte) [][]byte {
+	var streams [][]byte
+	for _, match := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(match[1]))
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		streams = append(streams, data)
+	}
+	return streams
+}
+
+func TestImageStream(t *testing.T) {
+	t.Run("images replace their placeholders in the finished file", func(t *testing.T) {
+		dir := t.TempDir()
+		jpegPath := writeTestImageSize(t, dir, "photo", 40, 56)
+		pngPath := writeTestPNG(t, dir, "scan", true)
+		g := NewGenerator(3.0).(*Generator)
+		outputPath := generateDeck(t, g, &model.Decklist{Cards: []model.CardEntry{
+			{Qty: 4, ID: "photo", Faces: []model.Face{{Name: "Photo", ImagePath: jpegPath}}},
+			{Qty: 2, ID: "scan", Faces: []model.Face{{Name: "Scan", ImagePath: pngPath}}},
+		}})
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		jpegData, err := os.ReadFile(jpegPath)
+		require.NoError(t, err)
+		require.True(t, bytes.Contains(data, jpegData), "the JPEG is copied as is")
+
+		file, err := os.Open(outputPath)
+		require.NoError(t, err)
+		defer file.Close()
+		doc, err := readStructure(file)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{jpegPath, pngPath}, g.images.order)
+		for _, path := range g.images.order {
+			id := g.images.images[path].id
+			require.Contains(t, string(data), fmt.Sprintf("/I%d %d 0 R", id, id), "pages use the image")
+			dict, err := doc.object(id)
+			require.NoError(t, err)
+			subtype, _ := dict.get("/Subtype")
+			require.Equal(t, "/Image", subtype)
+		}
+
+		photo, err := doc.object(g.images.images[jpegPath].id)
+		require.NoError(t, err)
+		width, _ := photo.get("/Width")
+		filter, _ := photo.get("/Filter")
+		require.Equal(t, "40", width)
+		require.Equal(t, "/DCTDecode", filter)
+
+		scan, err := doc.object(g.images.images[pngPath].id)
+		require.NoError(t, err)
+		filter, _ = scan.get("/Filter")
+		require.Equal(t, "/FlateDecode", filter)
+		maskRef, ok := scan.get("/SMask")
+		require.True(t, ok)
+		maskID, err := reference(maskRef)
+		require.NoError(t, err)
+		mask, err := doc.object(maskID)
+		require.NoError(t, err)
+		colorSpace, _ := mask.get("/ColorSpace")
+		require.Equal(t, "/DeviceGray", colorSpace)
+	})
+
+	t.Run("PNG pixels are written as RGB with an alpha mask", func(t *testing.T) {
+		path := writeTestPNG(t, t.TempDir(), "scan", true)
+		config, format, err := decodeConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, "png", format)
+
+		var buf bytes.Buffer
+		update := newIncrementalUpdate(&buf, 0, 10)
+		require.NoError(t, writePNG(update, streamedImage{id: 5, format: format, config: config}, path))
+		require.NoError(t, update.w.Flush())
+
+		streams := streamData(t, buf.Bytes())
+		require.Len(t, streams, 2)
+		require.Equal(t, []byte{0, 0, 0, 0, 255, 255, 255, 255}, streams[0])
+		require.Equal(t, []byte{
+			0, 0, 7, 60, 0, 7, 120, 0, 7, 180, 0, 7,
+			0, 100, 7, 60, 100, 7, 120, 100, 7, 180, 100, 7,
+		}, streams[1])
+		require.Contains(t, buf.String(), "/SMask 10 0 R")
+		require.Contains(t, buf.String(), "/Length 12 0 R")
+	})
+
+	t.Run("opaque PNGs have no mask", func(t *testing.T) {
+		path := writeTestPNG(t, t.TempDir(), "scan", false)
+		config, format, err := decodeConfig(path)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		update := newIncrementalUpdate(&buf, 0, 10)
+		require.NoError(t, writePNG(update, streamedImage{id: 5, format: format, config: config}, path))
+		require.NoError(t, update.w.Flush())
+		require.Len(t, streamData(t, buf.Bytes()), 1)
+		require.NotContains(t, buf.String(), "/SMask")
+	})
+}
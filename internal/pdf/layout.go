@@ -0,0 +1,232 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/signintech/gopdf"
+)
+
+// CardSize is the trim size of a physical card in mm
+type CardSize struct {
+	Name   string
+	Width  float64
+	Height float64
+}
+
+// Card size profiles
+var (
+	CardSizeStandard  = CardSize{Name: "standard", Width: CardWidth, Height: CardHeight}
+	CardSizePoker     = CardSize{Name: "poker", Width: 63.5, Height: 88.9}
+	CardSizeJapanese  = CardSize{Name: "japanese", Width: 59.0, Height: 86.0}
+	CardSizeOversized = CardSize{Name: "oversized", Width: 127.0, Height: 177.8}
+)
+
+// cardSizes lists every named card size profile
+var cardSizes = []CardSize{CardSizeStandard, CardSizePoker, CardSizeJapanese, CardSizeOversized}
+
+// PageSize is the size of the output page in mm.
+// The zero value sizes every page to its card plus bleed.
+type PageSize struct {
+	Name   string
+	Width  float64
+	Height float64
+}
+
+// Named sheet sizes
+var (
+	PageSizeLetter  = PageSize{Name: "letter", Width: 215.9, Height: 279.4}
+	PageSizeA4      = PageSize{Name: "a4", Width: 210.0, Height: 297.0}
+	PageSizeA3      = PageSize{Name: "a3", Width: 297.0, Height: 420.0}
+	PageSizeTabloid = PageSize{Name: "tabloid", Width: 279.4, Height: 431.8}
+)
+
+// pageSizes lists every named sheet size
+var pageSizes = []PageSize{PageSizeLetter, PageSizeA4, PageSizeA3, PageSizeTabloid}
+
+// SheetMargin is the unprintable margin kept around the card grid on sheets, in mm
+const SheetMargin = 5.0
+
+// ParseCardSize looks up a card size profile by name
+func ParseCardSize(name string) (CardSize, error) {
+	for _, size := range cardSizes {
+		if strings.EqualFold(name, size.Name) {
+			return size, nil
+		}
+	}
+	return CardSize{}, fmt.Errorf("unknown card size '%s'", name)
+}
+
+// ParsePageSize parses a named sheet size or custom WIDTHxHEIGHT dimensions in mm.
+// An empty value or "card" selects one card per page.
+func ParsePageSize(value string) (PageSize, error) {
+	if value == "" || strings.EqualFold(value, "card") {
+		return PageSize{}, nil
+	}
+
+	for _, size := range pageSizes {
+		if strings.EqualFold(value, size.Name) {
+			return size, nil
+		}
+	}
+
+	parts := strings.Split(strings.ToLower(value), "x")
+	if len(parts) != 2 {
+		return PageSize{}, fmt.Errorf("unknown page size '%s'", value)
+	}
+	width, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return PageSize{}, fmt.Errorf("invalid page width in '%s': %w", value, err)
+	}
+	height, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return PageSize{}, fmt.Errorf("invalid page height in '%s': %w", value, err)
+	}
+	if width <= 0 || height <= 0 {
+		return PageSize{}, fmt.Errorf("page size must be positive, got '%s'", value)
+	}
+	return PageSize{Name: "custom", Width: width, Height: height}, nil
+}
+
+// IsSheet reports whether cards are laid out in a grid rather than one per page
+func (p PageSize) IsSheet() bool {
+	return p.Width > 0 && p.Height > 0
+}
+
+// cardSizeFor picks the card size for a card, using the oversized profile for
+// planes, schemes and other oversized printings
+func cardSizeFor(card scryfall.Card, base CardSize) CardSize {
+	if card.Oversized {
+		return CardSizeOversized
+	}
+	return base
+}
+
+// pageWriter places cards onto pages, starting a new page whenever the
+// current one is full or the card size changes
+type pageWriter struct {
+	doc      *gopdf.GoPdf
+	pageSize PageSize
+	bleed    float64
+	labels   []string // Label of each page, taken from its first card
+	duplex   bool     // Every front page is followed by its back page
+	open     bool     // The current page is a duplex front still waiting for its back
+
+	cardSize CardSize
+	height   float64 // Height of the current page
+	cols     int
+	rows     int
+	next     int
+	originX  float64
+	originY  float64
+}
+
+// newPageWriter creates a writer for the given document and page size
+func newPageWriter(doc *gopdf.GoPdf, pageSize PageSize, bleed float64) *pageWriter {
+	return &pageWriter{doc: doc, pageSize: pageSize, bleed: bleed}
+}
+
+// slot returns the upper-left corner of the bleed box for the next card.
+// The label names the page when the card is the first one placed on it.
+func (w *pageWriter) slot(size CardSize, label string) (float64, float64, error) {
+	if w.cols == 0 || w.next >= w.cols*w.rows || size != w.cardSize {
+		if w.duplex {
+			if err := w.closeSheet(label); err != nil {
+				return 0, 0, err
+			}
+		}
+		if err := w.startPage(size, label); err != nil {
+			return 0, 0, err
+		}
+		w.open = w.duplex
+	}
+
+	x, y := w.slotAt(w.next, false)
+	w.next++
+	return x, y, nil
+}
+
+// slotAt returns the upper-left corner of the bleed box in slot i of the
+// current page. Mirrored slots are the backs of a duplex sheet flipped on its
+// long edge, so each back lands behind its front.
+func (w *pageWriter) slotAt(i int, mirror bool) (float64, float64) {
+	col := i % w.cols
+	row := i / w.cols
+	if mirror {
+		col = w.cols - 1 - col
+	}
+
+	boxWidth := w.cardSize.Width + 2*w.bleed
+	boxHeight := w.cardSize.Height + 2*w.bleed
+	return w.originX + float64(col)*boxWidth, w.originY + float64(row)*boxHeight
+}
+
+// startFront starts a page for the fronts of duplex cards and returns how
+// many fit on it. Fronts always start a new sheet, leaving a blank back
+// behind the previous front first when needed.
+func (w *pageWriter) startFront(size CardSize, label string) (int, error) {
+	if err := w.closeSheet(label); err != nil {
+		return 0, err
+	}
+	if err := w.startPage(size, label); err != nil {
+		return 0, err
+	}
+	w.open = true
+	return w.cols * w.rows, nil
+}
+
+// startBack starts the back page of a duplex sheet. Its free slots are not
+// used by later cards, which start on a new page.
+func (w *pageWriter) startBack(size CardSize, label string) error {
+	if err := w.startPage(size, label); err != nil {
+		return err
+	}
+	w.next = w.cols * w.rows
+	w.open = false
+	return nil
+}
+
+// closeSheet adds a blank back page, sized like the front, when the current
+// page is a duplex front without its back, so the next page starts a new sheet
+func (w *pageWriter) closeSheet(label string) error {
+	if !w.open {
+		return nil
+	}
+	w.open = false
+	return w.startPage(w.cardSize, label)
+}
+
+// page returns the 1-based number of the current page
+func (w *pageWriter) page() int {
+	return len(w.labels)
+}
+
+// startPage adds a page and computes the card grid for it
+func (w *pageWriter) startPage(size CardSize, label string) error {
+	boxWidth := size.Width + 2*w.bleed
+	boxHeight := size.Height + 2*w.bleed
+
+	pageWidth, pageHeight := boxWidth, boxHeight
+	cols, rows := 1, 1
+	if w.pageSize.IsSheet() {
+		pageWidth, pageHeight = w.pageSize.Width, w.pageSize.Height
+		cols = int((pageWidth - 2*SheetMargin) / boxWidth)
+		rows = int((pageHeight - 2*SheetMargin) / boxHeight)
+		if cols < 1 || rows < 1 {
+			return fmt.Errorf("%s card (%.1fx%.1f mm with bleed) does not fit on a %.1fx%.1f mm page",
+				size.Name, boxWidth, boxHeight, pageWidth, pageHeight)
+		}
+	}
+
+	w.doc.AddPageWithOption(gopdf.PageOption{PageSize: &gopdf.Rect{W: pageWidth, H: pageHeight}})
+	w.labels = append(w.labels, label)
+	w.cardSize = size
+	w.height = pageHeight
+	w.cols, w.rows, w.next = cols, rows, 0
+	// Center the grid on the page
+	w.originX = (pageWidth - float64(cols)*boxWidth) / 2
+	w.originY = (pageHeight - float64(rows)*boxHeight) / 2
+	return nil
+}
@@ -0,0 +1,172 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/signintech/gopdf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCardSize(t *testing.T) {
+	t.Run("named profiles", func(t *testing.T) {
+		size, err := ParseCardSize("Poker")
+		require.NoError(t, err)
+		require.Equal(t, CardSizePoker, size)
+
+		size, err = ParseCardSize("japanese")
+		require.NoError(t, err)
+		require.Equal(t, 59.0, size.Width)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := ParseCardSize("tarot")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown card size")
+	})
+}
+
+func TestParsePageSize(t *testing.T) {
+	t.Run("card per page", func(t *testing.T) {
+		size, err := ParsePageSize("")
+		require.NoError(t, err)
+		require.False(t, size.IsSheet())
+
+		size, err = ParsePageSize("card")
+		require.NoError(t, err)
+		require.False(t, size.IsSheet())
+	})
+
+	t.Run("named sheet sizes", func(t *testing.T) {
+		size, err := ParsePageSize("A4")
+		require.NoError(t, err)
+		require.Equal(t, PageSizeA4, size)
+		require.True(t, size.IsSheet())
+
+		size, err = ParsePageSize("tabloid")
+		require.NoError(t, err)
+		require.Equal(t, PageSizeTabloid, size)
+	})
+
+	t.Run("custom dimensions", func(t *testing.T) {
+		size, err := ParsePageSize("300x450.5")
+		require.NoError(t, err)
+		require.Equal(t, 300.0, size.Width)
+		require.Equal(t, 450.5, size.Height)
+	})
+
+	t.Run("invalid dimensions", func(t *testing.T) {
+		_, err := ParsePageSize("wide")
+		require.Error(t, err)
+
+		_, err = ParsePageSize("100xtall")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid page height")
+
+		_, err = ParsePageSize("0x100")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be positive")
+	})
+}
+
+func TestCardSizeFor(t *testing.T) {
+	require.Equal(t, CardSizePoker, cardSizeFor(scryfall.Card{}, CardSizePoker))
+	require.Equal(t, CardSizeOversized, cardSizeFor(scryfall.Card{Oversized: true}, CardSizePoker))
+}
+
+func newTestWriter(pageSize PageSize, bleed float64) *pageWriter {
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: *gopdf.PageSizeA4})
+	return newPageWriter(doc, pageSize, bleed)
+}
+
+func TestPageWriter(t *testing.T) {
+	t.Run("one card per page", func(t *testing.T) {
+		w := newTestWriter(PageSize{}, 3.0)
+		for i := 0; i < 3; i++ {
+			x, y, err := w.slot(CardSizeStandard, "Card")
+			require.NoError(t, err)
+			require.Equal(t, 0.0, x)
+			require.Equal(t, 0.0, y)
+		}
+		require.Equal(t, 3, w.doc.GetNumberOfPages())
+		require.Equal(t, 3, w.page())
+		require.Equal(t, []string{"Card", "Card", "Card"}, w.labels)
+	})
+
+	t.Run("nine standard cards fill an A4 sheet", func(t *testing.T) {
+		w := newTestWriter(PageSizeA4, 0)
+		for i := 0; i < 9; i++ {
+			_, _, err := w.slot(CardSizeStandard, "Card")
+			require.NoError(t, err)
+		}
+		require.Equal(t, 1, w.doc.GetNumberOfPages())
+		require.Equal(t, 3, w.cols)
+		require.Equal(t, 3, w.rows)
+
+		x, y, err := w.slot(CardSizeStandard, "Card")
+		require.NoError(t, err)
+		require.Equal(t, 2, w.doc.GetNumberOfPages())
+		require.InDelta(t, (210.0-3*63.0)/2, x, 0.001)
+		require.InDelta(t, (297.0-3*88.0)/2, y, 0.001)
+	})
+
+	t.Run("card size change starts a new sheet", func(t *testing.T) {
+		w := newTestWriter(PageSizeLetter, 1.0)
+		_, _, err := w.slot(CardSizeStandard, "Card")
+		require.NoError(t, err)
+		_, _, err = w.slot(CardSizeOversized, "Card")
+		require.NoError(t, err)
+		require.Equal(t, 2, w.doc.GetNumberOfPages())
+		require.Equal(t, 1, w.cols)
+	})
+
+	t.Run("duplex fronts start on odd pages with mirrored backs", func(t *testing.T) {
+		w := newTestWriter(PageSizeA4, 0)
+		w.duplex = true
+		_, _, err := w.slot(CardSizeStandard, "Single")
+		require.NoError(t, err)
+
+		count, err := w.startFront(CardSizeStandard, "Front")
+		require.NoError(t, err)
+		require.Equal(t, 9, count)
+		require.Equal(t, 3, w.page()) // Page 2 is left blank behind the single card
+		frontX, frontY := w.slotAt(0, false)
+
+		require.NoError(t, w.startBack(CardSizeStandard, "Back"))
+		backX, backY := w.slotAt(0, true)
+		require.InDelta(t, frontX+2*63.0, backX, 0.001)
+		require.Equal(t, frontY, backY)
+
+		// Later cards do not share the back page
+		_, _, err = w.slot(CardSizeStandard, "Next")
+		require.NoError(t, err)
+		require.Equal(t, []string{"Single", "Front", "Front", "Back", "Next"}, w.labels)
+	})
+
+	t.Run("blank backs follow the side of the page, not its number", func(t *testing.T) {
+		w := newTestWriter(PageSizeA4, 0)
+		w.duplex = true
+		// A one-sided insert, such as a text page, shifts the page parity
+		require.NoError(t, w.startPage(CardSizeStandard, "Insert"))
+
+		_, err := w.startFront(CardSizeStandard, "Front")
+		require.NoError(t, err)
+		require.Equal(t, 2, w.page(), "no blank back behind the insert")
+		require.NoError(t, w.startBack(CardSizeStandard, "Back"))
+
+		_, _, err = w.slot(CardSizeStandard, "Single")
+		require.NoError(t, err)
+		require.NoError(t, w.closeSheet("Single"))
+		require.Equal(t, []string{"Insert", "Front", "Back", "Single", "Single"}, w.labels)
+		require.NoError(t, w.closeSheet("Single"), "a closed sheet gets no second blank")
+		require.Equal(t, 5, w.page())
+	})
+
+	t.Run("card larger than the sheet", func(t *testing.T) {
+		w := newTestWriter(PageSize{Name: "custom", Width: 100, Height: 150}, 0)
+		_, _, err := w.slot(CardSizeOversized, "Card")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not fit")
+	})
+}
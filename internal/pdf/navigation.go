@@ -0,0 +1,232 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/signintech/gopdf"
+)
+
+// outlineNode is a bookmark and, for a section, the cards under it
+type outlineNode struct {
+	item     *gopdf.OutlineObj
+	children []*gopdf.OutlineObj
+}
+
+// outlineBuilder adds one bookmark per unique card to the document, grouped
+// by section when the deck has sections. Bookmarks point at the current page,
+// so cards are added while their first page is the one being written.
+type outlineBuilder struct {
+	doc           *gopdf.GoPdf
+	defaultHeight float64 // Height of the document's default page, in mm
+	nested        bool    // Cards are grouped under their section
+	top           []*outlineNode
+	sections      map[string]*outlineNode
+	seen          map[string]bool
+}
+
+// newOutlineBuilder creates an empty outline for a document whose default
+// page is defaultHeight mm tall. Cards are grouped by section when nested.
+func newOutlineBuilder(doc *gopdf.GoPdf, defaultHeight float64, nested bool) *outlineBuilder {
+	return &outlineBuilder{
+		doc:           doc,
+		defaultHeight: defaultHeight,
+		nested:        nested,
+		sections:      make(map[string]*outlineNode),
+		seen:          make(map[string]bool),
+	}
+}
+
+// hasSections reports whether any card in the decklist is in a named section
+func hasSections(decklist *model.Decklist) bool {
+	for _, entry := range decklist.Cards {
+		if entry.Section != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// addCard bookmarks the current page, pageHeight mm tall, for a card.
+// Repeated cards are ignored.
+func (b *outlineBuilder) addCard(section, key, title string, pageHeight float64) {
+	if b.seen[key] {
+		return
+	}
+	b.seen[key] = true
+
+	if !b.nested {
+		b.top = append(b.top, &outlineNode{item: b.add(title, pageHeight)})
+		return
+	}
+	node, ok := b.sections[section]
+	if !ok {
+		name := section
+		if name == "" {
+			// Cards without a section, in a deck that has other sections
+			name = model.MainSection
+		}
+		node = &outlineNode{item: b.add(name, pageHeight)}
+		b.sections[section] = node
+		b.top = append(b.top, node)
+	}
+	node.children = append(node.children, b.add(title, pageHeight))
+}
+
+// addPage bookmarks the current page, such as statistics after the cards,
+// at the top level
+func (b *outlineBuilder) addPage(title string, pageHeight float64) {
+	b.top = append(b.top, &outlineNode{item: b.add(title, pageHeight)})
+}
+
+// add creates a bookmark that opens the current page at its top. gopdf
+// measures the destination from the default page height and the cursor.
+func (b *outlineBuilder) add(title string, pageHeight float64) *gopdf.OutlineObj {
+	b.doc.SetY(b.defaultHeight - pageHeight)
+	return b.doc.AddOutlineWithPosition(title)
+}
+
+// link chains the bookmarks into their tree. gopdf links every bookmark as a
+// sibling of the one added before it, so sections and their cards are
+// relinked here. It returns the outline's first and last top-level bookmark
+// IDs, which gopdf's root only gets right for a flat outline, and the number
+// of top-level bookmarks.
+func (b *outlineBuilder) link() outlineRoot {
+	if len(b.top) == 0 {
+		return outlineRoot{}
+	}
+	items := make([]*gopdf.OutlineObj, len(b.top))
+	for i, node := range b.top {
+		items[i] = node.item
+		linkSiblings(node.children)
+		if len(node.children) > 0 {
+			node.item.SetFirst(node.children[0].GetIndex())
+			node.item.SetLast(node.children[len(node.children)-1].GetIndex())
+		}
+		for _, child := range node.children {
+			child.SetParent(node.item.GetIndex())
+		}
+	}
+	for _, item := range items {
+		item.SetParent(outlinesObjectID)
+	}
+	linkSiblings(items)
+	return outlineRoot{First: items[0].GetIndex(), Last: items[len(items)-1].GetIndex(), Count: len(items)}
+}
+
+// outlinesObjectID is the object ID of the outline root. gopdf adds the
+// catalog, pages and outlines objects first, but points top-level bookmarks
+// at the outline root's array index instead of its object ID.
+const outlinesObjectID = 3
+
+// linkSiblings chains bookmarks of the same level in order
+func linkSiblings(items []*gopdf.OutlineObj) {
+	for i, item := range items {
+		item.SetPrev(-1)
+		item.SetNext(-1)
+		if i > 0 {
+			item.SetPrev(items[i-1].GetIndex())
+		}
+		if i < len(items)-1 {
+			item.SetNext(items[i+1].GetIndex())
+		}
+	}
+}
+
+// outlineRoot is the top level of a finished outline
+type outlineRoot struct {
+	First int // Object ID of the first top-level bookmark, 0 without bookmarks
+	Last  int
+	Count int
+}
+
+// labelRange is a run of consecutive pages sharing a label prefix
+type labelRange struct {
+	Start int // 0-based index of the first page
+	Count int
+	Label string
+}
+
+// labelRanges collapses per-page labels into ranges of identical labels
+func labelRanges(labels []string) []labelRange {
+	var ranges []labelRange
+	for i, label := range labels {
+		if n := len(ranges); n > 0 && ranges[n-1].Label == label {
+			ranges[n-1].Count++
+			continue
+		}
+		ranges = append(ranges, labelRange{Start: i, Count: 1, Label: label})
+	}
+	return ranges
+}
+
+// writeNavigation adds page labels to a finished PDF with a new revision of
+// its catalog, copied from the original with /PageLabels added. The outline
+// root is rewritten with the first and last top-level bookmarks, since gopdf
+// links nested bookmarks as one flat list.
+func writeNavigation(update *incrementalUpdate, doc *pdfStructure, outline outlineRoot, labels []string) error {
+	rootRef, _ := doc.trailer.get("/Root")
+	root, err := reference(rootRef)
+	if err != nil {
+		return fmt.Errorf("failed to find PDF catalog: %w", err)
+	}
+	catalog, err := doc.object(root)
+	if err != nil {
+		return err
+	}
+
+	if outline.Count > 0 {
+		outlinesRef, _ := catalog.get("/Outlines")
+		outlines, err := reference(outlinesRef)
+		if err != nil {
+			return fmt.Errorf("failed to find PDF outline: %w", err)
+		}
+		if outlines != outlinesObjectID {
+			return fmt.Errorf("failed to find PDF outline: unexpected object %d", outlines)
+		}
+		dict := pdfDict{
+			{Key: "/Type", Value: "/Outlines"},
+			{Key: "/First", Value: fmt.Sprintf("%d 0 R", outline.First)},
+			{Key: "/Last", Value: fmt.Sprintf("%d 0 R", outline.Last)},
+			{Key: "/Count", Value: strconv.Itoa(outline.Count)},
+		}
+		if err := update.writeObject(outlines, dict.String()); err != nil {
+			return err
+		}
+	}
+	if ranges := labelRanges(labels); len(ranges) > 0 {
+		catalog = catalog.set("/PageLabels", pageLabels(ranges))
+	}
+	return update.writeObject(root, catalog.String())
+}
+
+// pageLabels builds a page label number tree. Runs of pages share their
+// card name and are numbered within the run.
+func pageLabels(ranges []labelRange) string {
+	var b strings.Builder
+	b.WriteString("<< /Nums [")
+	for _, r := range ranges {
+		if r.Count == 1 {
+			fmt.Fprintf(&b, " %d << /P %s >>", r.Start, pdfText(r.Label))
+		} else {
+			fmt.Fprintf(&b, " %d << /S /D /P %s /St 1 >>", r.Start, pdfText(r.Label+" "))
+		}
+	}
+	b.WriteString(" ] >>")
+	return b.String()
+}
+
+// pdfText encodes a string as a UTF-16BE hex string so card names with
+// accents or punctuation are safe in PDF dictionaries
+func pdfText(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package pdf
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/signintech/gopdf"
+	"github.com/stretchr/testify/require"
+)
+
+// readOutline walks the outline of a PDF and returns its bookmark titles,
+// indented by level, checking each level's links on the way
+func readOutline(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	doc, err := readStructure(file)
+	require.NoError(t, err)
+
+	object := func(ref string) (int, pdfDict) {
+		id, err := reference(ref)
+		require.NoError(t, err)
+		dict, err := doc.object(id)
+		require.NoError(t, err)
+		return id, dict
+	}
+	rootRef, _ := doc.trailer.get("/Root")
+	_, catalog := object(rootRef)
+	outlinesRef, ok := catalog.get("/Outlines")
+	if !ok {
+		return nil
+	}
+
+	var titles []string
+	var walk func(parentID int, parent pdfDict, indent string) int
+	walk = func(parentID int, parent pdfDict, indent string) int {
+		ref, ok := parent.get("/First")
+		if !ok {
+			return 0
+		}
+		count, prev := 0, ""
+		for {
+			id, item := object(ref)
+			count++
+			title, _ := item.get("/Title")
+			titles = append(titles, indent+decodePDFText(t, title))
+			parentRef, _ := item.get("/Parent")
+			require.Equal(t, fmt.Sprintf("%d 0 R", parentID), parentRef)
+			prevRef, _ := item.get("/Prev")
+			require.Equal(t, prev, prevRef)
+			walk(id, item, indent+"  ")
+
+			next, ok := item.get("/Next")
+			if !ok {
+				last, _ := parent.get("/Last")
+				require.Equal(t, ref, last)
+				return count
+			}
+			prev, ref = ref, next
+		}
+	}
+	outlinesID, outlines := object(outlinesRef)
+	count := walk(outlinesID, outlines, "")
+	rootCount, _ := outlines.get("/Count")
+	require.Equal(t, fmt.Sprint(count), rootCount)
+	return titles
+}
+
+// decodePDFText decodes a UTF-16BE hex string written by pdfText or gopdf
+func decodePDFText(t *testing.T, value string) string {
+	data, err := hex.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, "<FEFF"), ">"))
+	require.NoError(t, err)
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// generateDeck renders cards to a PDF the way the render driver does
+func generateDeck(t *testing.T, g *Generator, decklist *model.Decklist) string {
+	outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+	g.SetOutputPath(outputPath)
+	require.NoError(t, g.Start(decklist))
+	for _, card := range decklist.Cards {
+		require.NoError(t, g.AddCard(card))
+	}
+	require.NoError(t, g.Finish())
+	return outputPath
+}
+
+func TestOutline(t *testing.T) {
+	t.Run("flat outline without sections", func(t *testing.T) {
+		cards := testDeck(t, 2)
+		repeat := cards[0]
+		outputPath := generateDeck(t, NewGenerator(3.0).(*Generator), &model.Decklist{Cards: append(cards, repeat)})
+		require.Equal(t, []string{"card-0", "card-1"}, readOutline(t, outputPath))
+	})
+
+	t.Run("grouped by section", func(t *testing.T) {
+		cards := testDeck(t, 3)
+		cards[0].Section = "Commander"
+		cards[2].Section = "Commander"
+		g := NewGenerator(3.0).(*Generator)
+		g.SetTextPage(TextPage{Label: "Statistics", Lines: []string{"Cards: 12"}})
+		outputPath := generateDeck(t, g, &model.Decklist{Cards: cards})
+
+		require.Equal(t, []string{
+			"Commander", "  card-0", "  card-2",
+			model.MainSection, "  card-1",
+			"Statistics",
+		}, readOutline(t, outputPath))
+	})
+
+	t.Run("cards point at their first page", func(t *testing.T) {
+		cards := testDeck(t, 2)
+		g := NewGenerator(3.0).(*Generator)
+		g.SetPageSize(PageSizeA4)
+		outputPath := generateDeck(t, g, &model.Decklist{Cards: cards})
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		// Both cards start on the first sheet, opened at the top of the A4 page
+		require.Equal(t, 2, strings.Count(string(data), "/Dest [ 5 0 R /XYZ 90 861.889764 0 ]"))
+	})
+}
+
+func TestLabelRanges(t *testing.T) {
+	ranges := labelRanges([]string{"Bolt", "Bolt", "Bolt", "Island", "Bolt"})
+	require.Equal(t, []labelRange{
+		{Start: 0, Count: 3, Label: "Bolt"},
+		{Start: 3, Count: 1, Label: "Island"},
+		{Start: 4, Count: 1, Label: "Bolt"},
+	}, ranges)
+}
+
+func TestPDFText(t *testing.T) {
+	require.Equal(t, "<FEFF0041>", pdfText("A"))
+	require.Equal(t, "<FEFF004C00FB>", pdfText("Lû"))
+}
+
+func TestAppendNavigation(t *testing.T) {
+	cards := testDeck(t, 2)
+	g := NewGenerator(3.0).(*Generator)
+	outputPath := generateDeck(t, g, &model.Decklist{Name: "Test Deck", Source: "decks/test.csv", Cards: cards})
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	content := string(data)
+	require.Contains(t, content, "/PageLabels << /Nums [ 0 << /S /D")
+	require.Contains(t, content, " 4 << /S /D")
+	require.Contains(t, content, "/Prev ")
+	require.Equal(t, 2, strings.Count(content, "%%EOF"))
+
+	t.Run("keeps the catalog and trailer entries", func(t *testing.T) {
+		file, err := os.Open(outputPath)
+		require.NoError(t, err)
+		defer file.Close()
+		doc, err := readStructure(file)
+		require.NoError(t, err)
+
+		rootRef, _ := doc.trailer.get("/Root")
+		root, err := reference(rootRef)
+		require.NoError(t, err)
+		catalog, err := doc.object(root)
+		require.NoError(t, err)
+		for _, key := range []string{"/Type", "/Pages", "/PageMode", "/Outlines", "/PageLabels"} {
+			_, ok := catalog.get(key)
+			require.True(t, ok, "catalog keeps %s", key)
+		}
+
+		info, ok := doc.trailer.get("/Info")
+		require.True(t, ok)
+		require.Contains(t, info, "/Title "+pdfText("Test Deck"))
+	})
+
+	t.Run("the updated file is still readable", func(t *testing.T) {
+		reader := gopdf.GoPdf{}
+		reader.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: *gopdf.PageSizeA4})
+		require.NoError(t, reader.ImportPagesFromSource(outputPath, "/MediaBox"))
+		require.Equal(t, 8, reader.GetNumberOfPages())
+	})
+
+	t.Run("a file that is not a PDF is an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "deck.pdf")
+		require.NoError(t, os.WriteFile(path, []byte("not a pdf"), 0644))
+		require.Error(t, appendUpdate(path, func(update *incrementalUpdate, doc *pdfStructure) error {
+			return writeNavigation(update, doc, outlineRoot{}, []string{"Card"})
+		}))
+	})
+}
+
+func TestReadDict(t *testing.T) {
+	dict, err := readDict(bufio.NewReader(strings.NewReader(
+		"  <</Type /Catalog /Pages 2 0 R/Kids[3 0 R 4 0 R]/Title (A \\(escaped\\) and (nested) title)/Key<00FF>/Info <<\n/Title <FEFF0041>\n>> /N 5>>")))
+	require.NoError(t, err)
+	require.Equal(t, pdfDict{
+		{Key: "/Type", Value: "/Catalog"},
+		{Key: "/Pages", Value: "2 0 R"},
+		{Key: "/Kids", Value: "[3 0 R 4 0 R]"},
+		{Key: "/Title", Value: "(A \\(escaped\\) and (nested) title)"},
+		{Key: "/Key", Value: "<00FF>"},
+		{Key: "/Info", Value: "<<\n  /Title <FEFF0041>\n>>"},
+		{Key: "/N", Value: "5"},
+	}, dict)
+}
+
+func TestDocumentInfo(t *testing.T) {
+	generatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	info := documentInfo(&model.Decklist{Name: "Atraxa", Source: "/decks/atraxa.csv"}, generatedAt)
+	require.Equal(t, "Atraxa", info.Title)
+	require.Contains(t, info.Subject, "atraxa.csv")
+	require.Equal(t, generatedAt, info.CreationDate)
+
+	info = documentInfo(&model.Decklist{}, generatedAt)
+	require.Equal(t, "DeckForge Proxies", info.Title)
+}
@@ -0,0 +1,54 @@
+package pdf
+
+import (
+	"image"
+	_ "image/jpeg" // Register JPEG decoding for image.DecodeConfig
+	_ "image/png"  // Register PNG decoding for image.DecodeConfig
+	"os"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// landscapeLayouts are Scryfall layouts whose faces may be printed horizontally
+var landscapeLayouts = map[string]bool{
+	"planar": true,
+	"battle": true,
+	"split":  true,
+}
+
+// isLandscapeLayout reports whether a card's layout can have horizontal art.
+// Aftermath cards use the split layout but are also detected by keyword.
+func isLandscapeLayout(card scryfall.Card) bool {
+	if landscapeLayouts[card.Layout] {
+		return true
+	}
+	for _, keyword := range card.Keywords {
+		if strings.EqualFold(keyword, "Aftermath") {
+			return true
+		}
+	}
+	return false
+}
+
+// isLandscapeImage reports whether the image at path is wider than it is tall
+func isLandscapeImage(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return false
+	}
+	return config.Width > config.Height
+}
+
+// needsRotation reports whether a face image must be turned to fit a portrait
+// trim box. Only horizontal layouts are rotated, and only when the scan itself
+// is landscape (Scryfall serves some split cards already upright).
+func needsRotation(card scryfall.Card, imagePath string) bool {
+	return imagePath != "" && isLandscapeLayout(card) && isLandscapeImage(imagePath)
+}
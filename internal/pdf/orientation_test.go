@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLandscapeLayout(t *testing.T) {
+	require.True(t, isLandscapeLayout(scryfall.Card{Layout: "planar"}))
+	require.True(t, isLandscapeLayout(scryfall.Card{Layout: "battle"}))
+	require.True(t, isLandscapeLayout(scryfall.Card{Layout: "split"}))
+	require.True(t, isLandscapeLayout(scryfall.Card{Layout: "normal", Keywords: []string{"Aftermath"}}))
+	require.False(t, isLandscapeLayout(scryfall.Card{Layout: "normal"}))
+	require.False(t, isLandscapeLayout(scryfall.Card{Layout: "transform"}))
+}
+
+func TestNeedsRotation(t *testing.T) {
+	dir := t.TempDir()
+	portrait := writeTestImageSize(t, dir, "portrait", 488, 680)
+	landscape := writeTestImageSize(t, dir, "landscape", 680, 488)
+
+	t.Run("landscape scan of a horizontal layout", func(t *testing.T) {
+		require.True(t, needsRotation(scryfall.Card{Layout: "planar"}, landscape))
+	})
+
+	t.Run("upright scan of a horizontal layout", func(t *testing.T) {
+		require.False(t, needsRotation(scryfall.Card{Layout: "split"}, portrait))
+	})
+
+	t.Run("landscape scan of a regular layout", func(t *testing.T) {
+		require.False(t, needsRotation(scryfall.Card{Layout: "normal"}, landscape))
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		require.False(t, needsRotation(scryfall.Card{Layout: "battle"}, ""))
+		require.False(t, needsRotation(scryfall.Card{Layout: "battle"}, "does-not-exist.jpg"))
+	})
+
+	t.Run("rotated image is placed", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		doc := g.newDocument()
+		pages := newPageWriter(doc, g.pageSize, g.bleedAmount)
+		x, y, err := pages.slot(CardSizeOversized, "Plane")
+		require.NoError(t, err)
+		require.NoError(t, g.placeCard(doc, x, y, CardSizeOversized, landscape, true, false))
+		_, err = doc.GetBytesPdfReturnErr()
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// scaleImage resizes src to width x height pixels using bilinear sampling
+func scaleImage(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+	scaleX := float64(bounds.Dx()) / float64(width)
+	scaleY := float64(bounds.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		srcY := (float64(y)+0.5)*scaleY - 0.5
+		y0 := clampInt(int(math.Floor(srcY)), 0, bounds.Dy()-1)
+		y1 := clampInt(y0+1, 0, bounds.Dy()-1)
+		fy := srcY - math.Floor(srcY)
+
+		for x := 0; x < width; x++ {
+			srcX := (float64(x)+0.5)*scaleX - 0.5
+			x0 := clampInt(int(math.Floor(srcX)), 0, bounds.Dx()-1)
+			x1 := clampInt(x0+1, 0, bounds.Dx()-1)
+			fx := srcX - math.Floor(srcX)
+
+			c00 := color.RGBAModel.Convert(src.At(bounds.Min.X+x0, bounds.Min.Y+y0)).(color.RGBA)
+			c10 := color.RGBAModel.Convert(src.At(bounds.Min.X+x1, bounds.Min.Y+y0)).(color.RGBA)
+			c01 := color.RGBAModel.Convert(src.At(bounds.Min.X+x0, bounds.Min.Y+y1)).(color.RGBA)
+			c11 := color.RGBAModel.Convert(src.At(bounds.Min.X+x1, bounds.Min.Y+y1)).(color.RGBA)
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: lerpChannel(c00.R, c10.R, c01.R, c11.R, fx, fy),
+				G: lerpChannel(c00.G, c10.G, c01.G, c11.G, fx, fy),
+				B: lerpChannel(c00.B, c10.B, c01.B, c11.B, fx, fy),
+				A: lerpChannel(c00.A, c10.A, c01.A, c11.A, fx, fy),
+			})
+		}
+	}
+	return dst
+}
+
+// rotateImage90 turns src a quarter turn counter-clockwise, matching the
+// rotation used when placing landscape art in the PDF
+func rotateImage90(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dy(), bounds.Dx()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			dst.Set(y, bounds.Dx()-1-x, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// renderFaceImage draws a card face onto a black canvas of width x height
+// pixels, leaving bleed pixels of border on every side. Transparent corners,
+// as in Scryfall's PNG scans, show the black border.
+func renderFaceImage(src image.Image, width, height, bleed int, rotate bool) *image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	if src == nil {
+		return canvas
+	}
+	if rotate {
+		src = rotateImage90(src)
+	}
+
+	card := scaleImage(src, width-2*bleed, height-2*bleed)
+	draw.Draw(canvas, card.Bounds().Add(image.Point{X: bleed, Y: bleed}), card, image.Point{}, draw.Over)
+	return canvas
+}
+
+// mmToPixels converts a length in mm to pixels at the given DPI
+func mmToPixels(mm float64, dpi int) int {
+	return int(math.Round(mm / 25.4 * float64(dpi)))
+}
+
+func lerpChannel(c00, c10, c01, c11 uint8, fx, fy float64) uint8 {
+	top := float64(c00)*(1-fx) + float64(c10)*fx
+	bottom := float64(c01)*(1-fx) + float64(c11)*fx
+	return uint8(math.Round(top*(1-fy) + bottom*fy))
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
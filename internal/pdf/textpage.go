@@ -0,0 +1,121 @@
+package pdf
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/signintech/gopdf"
+	"golang.org/x/image/font/gofont/gomono"
+)
+
+// Text page typography
+const (
+	textFontFamily  = "mono"
+	maxTextFontSize = 11.0 // Points
+	monoAdvance     = 0.6  // Go Mono glyph width as a fraction of the font size
+	textLineHeight  = 1.25 // Line spacing as a multiple of the font size
+	pointsToMM      = 25.4 / 72
+	textCardBorder  = 3.0 // Black border inside the trim of a text card, in mm
+	textCardPadding = 2.0 // Space between the border and the text, in mm
+)
+
+// TextPage is a page of monospaced text appended after the cards, such as
+// deck statistics
+type TextPage struct {
+	Label string // Page label and bookmark title
+	Lines []string
+}
+
+// SetTextPage appends a page of text after the cards when the document is finished
+func (g *Generator) SetTextPage(page TextPage) {
+	g.textPage = page
+}
+
+// writeTextPage adds the text page, sized like the card pages and with the
+// font scaled down until every line fits
+func (g *Generator) writeTextPage() error {
+	width, height := g.TotalWidth(), g.TotalHeight()
+	if g.pageSize.IsSheet() {
+		width, height = g.pageSize.Width, g.pageSize.Height
+	}
+
+	fontSize := textFontSize(g.textPage.Lines, width-2*SheetMargin, height-2*SheetMargin)
+	if err := g.setFont(g.doc, fontSize); err != nil {
+		return err
+	}
+
+	g.doc.AddPageWithOption(gopdf.PageOption{PageSize: &gopdf.Rect{W: width, H: height}})
+	g.pages.labels = append(g.pages.labels, g.textPage.Label)
+	g.outline.addPage(g.textPage.Label, height)
+
+	g.doc.SetFillColor(0, 0, 0)
+	lineHeight := fontSize * textLineHeight * pointsToMM
+	for i, line := range g.textPage.Lines {
+		g.doc.SetXY(SheetMargin, SheetMargin+float64(i)*lineHeight)
+		if err := g.doc.Cell(nil, line); err != nil {
+			return fmt.Errorf("failed to write text: %w", err)
+		}
+	}
+	return nil
+}
+
+// placeText draws a card of text, such as a checklist insert, at x,y: black
+// bleed and border around a white box with the text fitted inside it
+func (g *Generator) placeText(doc *gopdf.GoPdf, x, y float64, size CardSize, lines []string, back bool) error {
+	if err := g.placeCard(doc, x, y, size, "", false, back); err != nil {
+		return err
+	}
+
+	boxX, boxY := g.calibration.point(x+g.bleedAmount+textCardBorder, y+g.bleedAmount+textCardBorder, back)
+	boxWidth, boxHeight := g.calibration.size(size.Width-2*textCardBorder, size.Height-2*textCardBorder)
+	if g.bleedAmount == 0 {
+		// Without bleed the card border is drawn here
+		outerX, outerY := g.calibration.point(x, y, back)
+		outerWidth, outerHeight := g.calibration.size(size.Width, size.Height)
+		doc.SetFillColor(0, 0, 0)
+		doc.RectFromUpperLeftWithStyle(outerX, outerY, outerWidth, outerHeight, "F")
+	}
+	doc.SetFillColor(255, 255, 255)
+	doc.RectFromUpperLeftWithStyle(boxX, boxY, boxWidth, boxHeight, "F")
+
+	fontSize := textFontSize(lines, boxWidth-2*textCardPadding, boxHeight-2*textCardPadding)
+	if err := g.setFont(doc, fontSize); err != nil {
+		return err
+	}
+	doc.SetFillColor(0, 0, 0)
+	doc.SetTextColor(0, 0, 0)
+	lineHeight := fontSize * textLineHeight * pointsToMM
+	for i, line := range lines {
+		doc.SetXY(boxX+textCardPadding, boxY+textCardPadding+float64(i)*lineHeight)
+		if err := doc.Cell(nil, line); err != nil {
+			return fmt.Errorf("failed to write text: %w", err)
+		}
+	}
+	return nil
+}
+
+// setFont loads the text font into the document on first use and selects it
+func (g *Generator) setFont(doc *gopdf.GoPdf, size float64) error {
+	if !g.fontLoaded {
+		if err := doc.AddTTFFontData(textFontFamily, gomono.TTF); err != nil {
+			return fmt.Errorf("failed to load font: %w", err)
+		}
+		g.fontLoaded = true
+	}
+	if err := doc.SetFont(textFontFamily, "", size); err != nil {
+		return fmt.Errorf("failed to set font: %w", err)
+	}
+	return nil
+}
+
+// textFontSize returns the largest font size, up to maxTextFontSize, at
+// which every line fits in a width x height mm box
+func textFontSize(lines []string, width, height float64) float64 {
+	longest := 1
+	for _, line := range lines {
+		longest = max(longest, utf8.RuneCountInString(line))
+	}
+	byWidth := width / (float64(longest) * monoAdvance * pointsToMM)
+	byHeight := height / (float64(max(len(lines), 1)) * textLineHeight * pointsToMM)
+	return min(maxTextFontSize, byWidth, byHeight)
+}
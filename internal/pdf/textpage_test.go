@@ -0,0 +1,42 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/signintech/gopdf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextPage(t *testing.T) {
+	t.Run("appended after the cards", func(t *testing.T) {
+		g := NewGenerator(3.0).(*Generator)
+		g.SetTextPage(TextPage{Label: "Statistics", Lines: []string{"Test: 4 card(s)", "", "Mana curve", "  1   4 ####"}})
+		outputPath := filepath.Join(t.TempDir(), "deck.pdf")
+		require.NoError(t, assembleStreaming(g, testDeck(t, 1), outputPath))
+
+		doc := gopdf.GoPdf{}
+		doc.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: gopdf.Rect{W: g.TotalWidth(), H: g.TotalHeight()}})
+		require.NoError(t, doc.ImportPagesFromSource(outputPath, "/MediaBox"))
+		require.Equal(t, 5, doc.GetNumberOfPages())
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Contains(t, string(data), pdfText("Statistics"))
+	})
+
+	t.Run("font shrinks to fit", func(t *testing.T) {
+		require.Equal(t, maxTextFontSize, textFontSize([]string{"short"}, 200, 200))
+
+		long := []string{strings.Repeat("x", 100)}
+		size := textFontSize(long, 59, 84)
+		require.Less(t, size, maxTextFontSize)
+		require.InDelta(t, 59.0, 100*size*monoAdvance*pointsToMM, 0.001)
+
+		many := make([]string, 60)
+		size = textFontSize(many, 59, 84)
+		require.InDelta(t, 84.0, 60*size*textLineHeight*pointsToMM, 0.001)
+	})
+}
@@ -0,0 +1,557 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// xrefTailSize is how much of the end of a PDF is read to find startxref
+const xrefTailSize = 1024
+
+// dictEntry is a key of a PDF dictionary with its value as written in the file
+type dictEntry struct {
+	Key   string // Name, including the leading slash
+	Value string
+}
+
+// pdfDict is a parsed PDF dictionary that keeps the order of its entries
+type pdfDict []dictEntry
+
+// get returns the value of a key
+func (d pdfDict) get(key string) (string, bool) {
+	for _, entry := range d {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return "", false
+}
+
+// set replaces the value of a key, or adds the key at the end
+func (d pdfDict) set(key, value string) pdfDict {
+	for i, entry := range d {
+		if entry.Key == key {
+			d[i].Value = value
+			return d
+		}
+	}
+	return append(d, dictEntry{Key: key, Value: value})
+}
+
+// without returns the dictionary without the given keys
+func (d pdfDict) without(keys ...string) pdfDict {
+	return slices.DeleteFunc(slices.Clone(d), func(entry dictEntry) bool {
+		return slices.Contains(keys, entry.Key)
+	})
+}
+
+// String writes the dictionary in PDF syntax
+func (d pdfDict) String() string {
+	var b strings.Builder
+	b.WriteString("<<\n")
+	for _, entry := range d {
+		fmt.Fprintf(&b, "  %s %s\n", entry.Key, entry.Value)
+	}
+	b.WriteString(">>")
+	return b.String()
+}
+
+// reference returns the object number of an indirect reference value
+func reference(value string) (int, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 3 || fields[2] != "R" {
+		return 0, fmt.Errorf("'%s' is not an object reference", value)
+	}
+	return strconv.Atoi(fields[0])
+}
+
+// xrefSection is a subsection of a cross-reference table
+type xrefSection struct {
+	first   int   // First object number
+	count   int   // Number of entries
+	entries int64 // File offset of the first entry
+}
+
+// pdfStructure is what an incremental update needs from the document it
+// extends: its trailer and where to find the objects it replaces. Only the
+// end of the file and the cross-reference tables are read.
+type pdfStructure struct {
+	file     *os.File
+	size     int64         // File size
+	xref     int64         // Offset of the last cross-reference table
+	sections []xrefSection // Newest first, so updated objects are found first
+	trailer  pdfDict       // The last trailer
+}
+
+// readStructure reads the trailer and cross-reference tables of a PDF with
+// classic cross-reference tables, seeking from the end of the file
+func readStructure(file *os.File) (*pdfStructure, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	s := &pdfStructure{file: file, size: info.Size()}
+
+	tail := make([]byte, min(s.size, xrefTailSize))
+	if _, err := file.ReadAt(tail, s.size-int64(len(tail))); err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	at := bytes.LastIndex(tail, []byte("startxref"))
+	if at < 0 {
+		return nil, fmt.Errorf("failed to find PDF cross-reference offset")
+	}
+	fields := strings.Fields(string(tail[at+len("startxref"):]))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("failed to find PDF cross-reference offset")
+	}
+	if s.xref, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("failed to read PDF cross-reference offset: %w", err)
+	}
+
+	// Follow the chain of earlier updates so every object can be found
+	seen := make(map[int64]bool)
+	for offset := s.xref; !seen[offset]; {
+		seen[offset] = true
+		sections, trailer, err := s.readXref(offset)
+		if err != nil {
+			return nil, err
+		}
+		s.sections = append(s.sections, sections...)
+		if s.trailer == nil {
+			s.trailer = trailer
+		}
+		prev, ok := trailer.get("/Prev")
+		if !ok {
+			break
+		}
+		if offset, err = strconv.ParseInt(prev, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to read PDF cross-reference offset: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// readXref reads the subsection headers of the cross-reference table at
+// offset, skipping the entries themselves, and the trailer after it
+func (s *pdfStructure) readXref(offset int64) ([]xrefSection, pdfDict, error) {
+	reader := bufio.NewReader(io.NewSectionReader(s.file, offset, s.size-offset))
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		offset += int64(len(line))
+		return strings.TrimSpace(line), err
+	}
+
+	if line, err := readLine(); err != nil || line != "xref" {
+		return nil, nil, fmt.Errorf("failed to find PDF cross-reference table")
+	}
+	var sections []xrefSection
+	for {
+		line, err := readLine()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read PDF cross-reference table: %w", err)
+		}
+		if rest, ok := strings.CutPrefix(line, "trailer"); ok {
+			reader = bufio.NewReader(io.MultiReader(strings.NewReader(rest+"\n"), reader))
+			break
+		}
+		var section xrefSection
+		if _, err := fmt.Sscanf(line, "%d %d", &section.first, &section.count); err != nil {
+			return nil, nil, fmt.Errorf("failed to read PDF cross-reference table: %w", err)
+		}
+		section.entries = offset
+		// Entries are fixed-width 20-byte lines
+		skip := int64(section.count) * 20
+		if _, err := reader.Discard(int(skip)); err != nil {
+			return nil, nil, fmt.Errorf("failed to read PDF cross-reference table: %w", err)
+		}
+		offset += skip
+		sections = append(sections, section)
+	}
+
+	trailer, err := readDict(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read PDF trailer: %w", err)
+	}
+	return sections, trailer, nil
+}
+
+// object reads the dictionary of an indirect object
+func (s *pdfStructure) object(id int) (pdfDict, error) {
+	offset, err := s.objectOffset(id)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(io.NewSectionReader(s.file, offset, s.size-offset))
+	var number, generation int
+	var keyword string
+	if _, err := fmt.Fscan(reader, &number, &generation, &keyword); err != nil || number != id || keyword != "obj" {
+		return nil, fmt.Errorf("failed to find PDF object %d", id)
+	}
+	dict, err := readDict(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF object %d: %w", id, err)
+	}
+	return dict, nil
+}
+
+// objectOffset looks up the file offset of an object in the cross-reference table
+func (s *pdfStructure) objectOffset(id int) (int64, error) {
+	for _, section := range s.sections {
+		if id < section.first || id >= section.first+section.count {
+			continue
+		}
+		entry := make([]byte, 20)
+		if _, err := s.file.ReadAt(entry, section.entries+int64(id-section.first)*20); err != nil {
+			return 0, fmt.Errorf("failed to read PDF cross-reference entry %d: %w", id, err)
+		}
+		fields := strings.Fields(string(entry))
+		if len(fields) != 3 || fields[2] != "n" {
+			return 0, fmt.Errorf("PDF object %d is not in use", id)
+		}
+		return strconv.ParseInt(fields[0], 10, 64)
+	}
+	return 0, fmt.Errorf("failed to find PDF object %d", id)
+}
+
+// referenceTail matches the generation number and R that follow the object
+// number of an indirect reference
+var referenceTail = regexp.MustCompile(`^\s+(\d+)\s+(R)(?:[\s/<>\[\]()%]|$)`)
+
+// pdfLexer reads PDF objects token by token
+type pdfLexer struct {
+	r *bufio.Reader
+}
+
+// readDict reads a dictionary, skipping leading whitespace
+func readDict(reader *bufio.Reader) (pdfDict, error) {
+	l := &pdfLexer{r: reader}
+	token, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+	if token != "<<" {
+		return nil, fmt.Errorf("expected a dictionary, found '%s'", token)
+	}
+	return l.dict()
+}
+
+// dict reads the entries of a dictionary after its opening <<
+func (l *pdfLexer) dict() (pdfDict, error) {
+	var dict pdfDict
+	for {
+		key, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		if key == ">>" {
+			return dict, nil
+		}
+		if !strings.HasPrefix(key, "/") {
+			return nil, fmt.Errorf("expected a name, found '%s'", key)
+		}
+		value, err := l.value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		dict = append(dict, dictEntry{Key: key, Value: value})
+	}
+}
+
+// value reads one object and returns it in PDF syntax
+func (l *pdfLexer) value() (string, error) {
+	token, err := l.token()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case token == "<<":
+		dict, err := l.dict()
+		if err != nil {
+			return "", err
+		}
+		return dict.String(), nil
+	case token == "[":
+		var items []string
+		for {
+			if next, err := l.peekToken(); err != nil {
+				return "", err
+			} else if next == ']' {
+				l.r.ReadByte()
+				return "[" + strings.Join(items, " ") + "]", nil
+			}
+			item, err := l.value()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+	case isNumber(token):
+		// An indirect reference is written as "number generation R"
+		rest, _ := l.r.Peek(32)
+		if match := referenceTail.FindSubmatchIndex(rest); match != nil {
+			l.r.Discard(match[5])
+			return fmt.Sprintf("%s %s R", token, rest[match[2]:match[3]]), nil
+		}
+		return token, nil
+	case token == ">>" || token == "]":
+		return "", fmt.Errorf("unexpected '%s'", token)
+	default:
+		return token, nil
+	}
+}
+
+// peekToken skips whitespace and comments and returns the next byte unread
+func (l *pdfLexer) peekToken() (byte, error) {
+	for {
+		c, err := l.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if c == '%' {
+			if _, err := l.r.ReadString('\n'); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return c, l.r.UnreadByte()
+		}
+	}
+}
+
+// token reads the next token: a dictionary or array delimiter, a name, a
+// string, or a number or keyword
+func (l *pdfLexer) token() (string, error) {
+	c, err := l.peekToken()
+	if err != nil {
+		return "", err
+	}
+	l.r.ReadByte()
+
+	var b strings.Builder
+	b.WriteByte(c)
+	switch c {
+	case '<', '>':
+		if next, _ := l.r.Peek(1); len(next) == 1 && next[0] == c {
+			l.r.ReadByte()
+			return string([]byte{c, c}), nil
+		}
+		if c == '>' {
+			return "", fmt.Errorf("unexpected '>'")
+		}
+		// Hex string
+		hex, err := l.r.ReadString('>')
+		return "<" + hex, err
+	case '[', ']':
+		return b.String(), nil
+	case '(':
+		return l.literalString()
+	}
+	for {
+		next, err := l.r.Peek(1)
+		if err != nil || isPDFSpace(next[0]) || strings.IndexByte("/<>[]()%", next[0]) >= 0 {
+			return b.String(), nil
+		}
+		l.r.ReadByte()
+		b.WriteByte(next[0])
+	}
+}
+
+// literalString reads a literal string after its opening parenthesis,
+// keeping escapes and balanced parentheses
+func (l *pdfLexer) literalString() (string, error) {
+	var b strings.Builder
+	b.WriteByte('(')
+	depth := 1
+	for depth > 0 {
+		c, err := l.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(c)
+		switch c {
+		case '\\':
+			escaped, err := l.r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(escaped)
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+	return b.String(), nil
+}
+
+// isNumber reports whether a token is an integer
+func isNumber(token string) bool {
+	_, err := strconv.Atoi(token)
+	return err == nil
+}
+
+// isPDFSpace reports whether a byte is PDF whitespace
+func isPDFSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', 0:
+		return true
+	}
+	return false
+}
+
+// appendUpdate appends an incremental update written by write to a finished
+// PDF. Only the trailer, cross-reference tables and the objects write asks for
+// are read; the original document is left untouched.
+func appendUpdate(path string, write func(update *incrementalUpdate, doc *pdfStructure) error) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer file.Close()
+
+	doc, err := readStructure(file)
+	if err != nil {
+		return err
+	}
+	sizeValue, _ := doc.trailer.get("/Size")
+	size, err := strconv.Atoi(sizeValue)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF size: %w", err)
+	}
+
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to seek to the end of the PDF: %w", err)
+	}
+	update := newIncrementalUpdate(file, doc.size, size)
+	if err := write(update, doc); err != nil {
+		return err
+	}
+	if err := update.finish(doc.trailer, doc.xref); err != nil {
+		return fmt.Errorf("failed to write PDF update: %w", err)
+	}
+	return nil
+}
+
+// incrementalUpdate appends new and replaced objects after the end of a
+// document, followed by a cross-reference section for them and a trailer
+// that points back to the original one
+type incrementalUpdate struct {
+	w       *bufio.Writer
+	offset  int64 // Offset in the file of the next byte written
+	nextID  int
+	offsets map[int]int64
+}
+
+// newIncrementalUpdate starts an update written to w, which appends to a
+// document of the given size whose next free object number is nextID
+func newIncrementalUpdate(w io.Writer, size int64, nextID int) *incrementalUpdate {
+	return &incrementalUpdate{w: bufio.NewWriter(w), offset: size, nextID: nextID, offsets: make(map[int]int64)}
+}
+
+// allocate reserves a new object number
+func (u *incrementalUpdate) allocate() int {
+	id := u.nextID
+	u.nextID++
+	return id
+}
+
+// write appends text and tracks the file offset
+func (u *incrementalUpdate) write(format string, args ...any) error {
+	n, err := fmt.Fprintf(u.w, format, args...)
+	u.offset += int64(n)
+	return err
+}
+
+// writeObject appends an object and records its offset for the xref table
+func (u *incrementalUpdate) writeObject(id int, body string) error {
+	if err := u.write("\n"); err != nil {
+		return err
+	}
+	u.offsets[id] = u.offset
+	return u.write("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// writeStream appends a stream object whose data is written by data. The
+// length is not known up front, so it is written as a separate object.
+func (u *incrementalUpdate) writeStream(id int, dict pdfDict, data func(w io.Writer) error) error {
+	length := u.allocate()
+	if err := u.write("\n"); err != nil {
+		return err
+	}
+	u.offsets[id] = u.offset
+	dict = dict.set("/Length", fmt.Sprintf("%d 0 R", length))
+	if err := u.write("%d 0 obj\n%s\nstream\n", id, dict); err != nil {
+		return err
+	}
+	counter := &countingWriter{w: u.w}
+	err := data(counter)
+	u.offset += counter.n
+	if err != nil {
+		return err
+	}
+	if err := u.write("\nendstream\nendobj\n"); err != nil {
+		return err
+	}
+	return u.writeObject(length, strconv.FormatInt(counter.n, 10))
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// finish appends the cross-reference section for the written objects and a
+// trailer carrying over the original trailer's entries
+func (u *incrementalUpdate) finish(trailer pdfDict, prevXref int64) error {
+	if err := u.write("\n"); err != nil {
+		return err
+	}
+	xrefOffset := u.offset
+	if err := u.write("xref\n"); err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(u.offsets))
+	for id := range u.offsets {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for start := 0; start < len(ids); {
+		end := start + 1
+		for end < len(ids) && ids[end] == ids[end-1]+1 {
+			end++
+		}
+		if err := u.write("%d %d\n", ids[start], end-start); err != nil {
+			return err
+		}
+		for _, id := range ids[start:end] {
+			if err := u.write("%010d 00000 n \n", u.offsets[id]); err != nil {
+				return err
+			}
+		}
+		start = end
+	}
+
+	trailer = trailer.without("/Prev").
+		set("/Size", strconv.Itoa(u.nextID)).
+		set("/Prev", strconv.FormatInt(prevXref, 10))
+	if err := u.write("trailer\n%s\nstartxref\n%d\n%%%%EOF\n", trailer, xrefOffset); err != nil {
+		return err
+	}
+	return u.w.Flush()
+}
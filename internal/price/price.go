@@ -0,0 +1,109 @@
+// Package price estimates what a deck would cost to buy, from the Scryfall
+// prices of its listed printings or of each card's cheapest printing.
+package price
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Currency is a Scryfall price currency
+type Currency string
+
+// Currencies
+const (
+	USD Currency = "usd"
+	EUR Currency = "eur"
+	Tix Currency = "tix" // MTGO event tickets
+)
+
+// ParseCurrency validates a currency name
+func ParseCurrency(value string) (Currency, error) {
+	switch currency := Currency(strings.ToLower(strings.TrimSpace(value))); currency {
+	case USD, EUR, Tix:
+		return currency, nil
+	default:
+		return "", fmt.Errorf("unknown currency '%s': use usd, eur or tix", value)
+	}
+}
+
+// Format prints an amount in the currency, e.g. $4.80, €3.10 or 0.12 tix
+func (c Currency) Format(amount Amount) string {
+	switch c {
+	case USD:
+		return "$" + amount.String()
+	case EUR:
+		return "€" + amount.String()
+	default:
+		return amount.String() + " " + string(c)
+	}
+}
+
+// Amount is a price in hundredths of the currency unit, so totals add up exactly
+type Amount int64
+
+// String formats the amount with two decimals
+func (a Amount) String() string {
+	return fmt.Sprintf("%d.%02d", a/100, a%100)
+}
+
+// MarshalJSON writes the amount as a decimal number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// parseAmount parses a Scryfall price string; empty prices are not available
+func parseAmount(value string) (Amount, bool) {
+	if value == "" {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return Amount(price*100 + 0.5), true
+}
+
+// cardPrice returns a printing's price in the currency for the entry's finish.
+// Entries without a finish use the nonfoil price, or the foil price for
+// foil-only printings. MTGO tickets have a single price for every finish.
+func cardPrice(prices scryfall.Prices, currency Currency, finish string) (Amount, bool) {
+	var candidates []string
+	switch currency {
+	case USD:
+		switch finish {
+		case model.FinishFoil:
+			candidates = []string{prices.USDFoil}
+		case model.FinishEtched:
+			candidates = []string{prices.USDEtched}
+		case model.FinishNonfoil:
+			candidates = []string{prices.USD}
+		default:
+			candidates = []string{prices.USD, prices.USDFoil, prices.USDEtched}
+		}
+	case EUR:
+		switch finish {
+		case model.FinishFoil:
+			candidates = []string{prices.EURFoil}
+		case model.FinishEtched:
+			// Scryfall has no etched EUR price
+		case model.FinishNonfoil:
+			candidates = []string{prices.EUR}
+		default:
+			candidates = []string{prices.EUR, prices.EURFoil}
+		}
+	case Tix:
+		candidates = []string{prices.Tix}
+	}
+
+	for _, candidate := range candidates {
+		if amount, ok := parseAmount(candidate); ok {
+			return amount, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package price
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := ParseCurrency("USD")
+	require.NoError(t, err)
+	require.Equal(t, USD, currency)
+
+	_, err = ParseCurrency("gbp")
+	require.ErrorContains(t, err, "unknown currency 'gbp'")
+}
+
+func TestCardPrice(t *testing.T) {
+	prices := scryfall.Prices{USD: "1.20", USDFoil: "3.05", USDEtched: "", EUR: "", EURFoil: "2.50", Tix: "0.03"}
+
+	tests := []struct {
+		currency Currency
+		finish   string
+		want     Amount
+		ok       bool
+	}{
+		{USD, "", 120, true},
+		{USD, model.FinishFoil, 305, true},
+		{USD, model.FinishEtched, 0, false},
+		{EUR, "", 250, true}, // Foil-only in EUR
+		{EUR, model.FinishNonfoil, 0, false},
+		{Tix, model.FinishFoil, 3, true},
+	}
+	for _, test := range tests {
+		got, ok := cardPrice(prices, test.currency, test.finish)
+		require.Equal(t, test.ok, ok, "%s %s", test.currency, test.finish)
+		require.Equal(t, test.want, got, "%s %s", test.currency, test.finish)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	printing := func(set string, usd string, digital bool) scryfall.Card {
+		return scryfall.Card{Name: "Lightning Bolt", OracleID: "bolt", Set: set, CollectorNumber: "1",
+			Digital: digital, Prices: scryfall.Prices{USD: usd, USDFoil: "9.99"}}
+	}
+	resolved := func(qty int, card scryfall.Card, section, finish string) model.CardEntry {
+		return model.CardEntry{Qty: qty, ID: card.Set, Section: section, Finish: finish, Card: card, Faces: []model.Face{{Name: card.Name}}}
+	}
+	decklist := &model.Decklist{Name: "Burn", Cards: []model.CardEntry{
+		resolved(4, printing("lea", "450.00", false), "", ""),
+		resolved(1, printing("lea", "450.00", false), "Sideboard", model.FinishFoil),
+		resolved(2, scryfall.Card{Name: "Unpriced", OracleID: "unpriced", Set: "plst"}, "", ""),
+		{Line: 4, Qty: 1, ID: "missing", Name: "Missing Card"},
+		resolved(4, printing("lea", "450.00", false), "Maybeboard", ""),
+	}}
+
+	t.Run("listed printings", func(t *testing.T) {
+		report := Estimate(decklist, USD, nil)
+		require.Equal(t, ModeListed, report.Mode)
+		require.Len(t, report.Cards, 4)
+		require.Equal(t, Amount(180000), report.Cards[0].Total)
+		require.Equal(t, Amount(999), report.Cards[1].Each)
+		require.True(t, report.Cards[2].Missing)
+		require.True(t, report.Cards[3].Missing)
+		require.Equal(t, 4, report.Cards[3].Line)
+
+		require.Equal(t, []Section{
+			{Name: "Mainboard", Total: 180000, Missing: 2},
+			{Name: "Sideboard", Total: 999},
+		}, report.Sections)
+		require.Equal(t, Amount(180999), report.Total)
+		require.Equal(t, 2, report.Missing)
+	})
+
+	t.Run("cheapest printings", func(t *testing.T) {
+		printings := map[string][]scryfall.Card{"bolt": {
+			printing("lea", "450.00", false),
+			printing("m10", "1.25", false),
+			printing("prm", "0.10", true), // Digital printings have no paper price
+			printing("sld", "", false),
+		}}
+		report := Estimate(decklist, USD, printings)
+		require.Equal(t, ModeCheapest, report.Mode)
+		require.Equal(t, "m10", report.Cards[0].Set)
+		require.Equal(t, Amount(500), report.Cards[0].Total)
+		require.Equal(t, Amount(500+999), report.Total)
+	})
+
+	t.Run("text and JSON output", func(t *testing.T) {
+		report := Estimate(decklist, USD, nil)
+
+		var out bytes.Buffer
+		require.NoError(t, report.WriteText(&out))
+		require.Contains(t, out.String(), "Lightning Bolt (lea 1)")
+		require.Contains(t, out.String(), "$450.00")
+		require.Contains(t, out.String(), "no price")
+		require.Contains(t, out.String(), "Total: $1809.99 (2 line(s) without a price)")
+
+		data, err := sonic.Marshal(report)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"total":1809.99`)
+	})
+}
@@ -0,0 +1,154 @@
+package price
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Pricing modes
+const (
+	ModeListed   = "listed"   // The printing in the decklist
+	ModeCheapest = "cheapest" // The cheapest printing of each card
+)
+
+// Card is the price of one decklist line
+type Card struct {
+	Line            int    `json:"line,omitempty"` // Zero for generated entries such as tokens
+	Quantity        int    `json:"quantity"`
+	Name            string `json:"name"`
+	Section         string `json:"section"`
+	Set             string `json:"set,omitempty"`
+	CollectorNumber string `json:"collector_number,omitempty"`
+	Finish          string `json:"finish,omitempty"`
+	Each            Amount `json:"each"`
+	Total           Amount `json:"total"`
+	Missing         bool   `json:"missing,omitempty"` // No price is available
+}
+
+// Section is the subtotal of a deck section
+type Section struct {
+	Name    string `json:"name"`
+	Total   Amount `json:"total"`
+	Missing int    `json:"missing"` // Lines without a price
+}
+
+// Report is the price estimate of a deck
+type Report struct {
+	Deck     string    `json:"deck"`
+	Currency Currency  `json:"currency"`
+	Mode     string    `json:"mode"`
+	Total    Amount    `json:"total"`
+	Missing  int       `json:"missing"` // Lines without a price
+	Sections []Section `json:"sections"`
+	Cards    []Card    `json:"cards"`
+}
+
+// Estimate prices every resolved entry of a decklist. With printings, keyed
+// by oracle ID, each card is priced at its cheapest printing for the entry's
+// finish; without, at the listed printing. Maybeboard entries are left out.
+func Estimate(decklist *model.Decklist, currency Currency, printings map[string][]scryfall.Card) *Report {
+	report := &Report{Deck: decklist.Name, Currency: currency, Mode: ModeListed, Sections: []Section{}, Cards: []Card{}}
+	if printings != nil {
+		report.Mode = ModeCheapest
+	}
+
+	sections := map[string]int{}
+	for _, entry := range decklist.Cards {
+		if entry.Board() == model.BoardNone {
+			continue
+		}
+
+		printing := entry.Card
+		if candidates, ok := printings[entry.Card.OracleID]; ok {
+			printing = cheapest(candidates, printing, currency, entry.Finish)
+		}
+		each, ok := cardPrice(printing.Prices, currency, entry.Finish)
+		card := Card{
+			Line:            entry.Line,
+			Quantity:        entry.Qty,
+			Name:            entry.DisplayName(),
+			Section:         entry.SectionName(),
+			Set:             printing.Set,
+			CollectorNumber: printing.CollectorNumber,
+			Finish:          entry.Finish,
+			Each:            each,
+			Total:           each * Amount(entry.Qty),
+			Missing:         !entry.Resolved() || !ok,
+		}
+		report.Cards = append(report.Cards, card)
+
+		index, seen := sections[card.Section]
+		if !seen {
+			index = len(report.Sections)
+			sections[card.Section] = index
+			report.Sections = append(report.Sections, Section{Name: card.Section})
+		}
+		report.Sections[index].Total += card.Total
+		report.Total += card.Total
+		if card.Missing {
+			report.Sections[index].Missing++
+			report.Missing++
+		}
+	}
+	return report
+}
+
+// cheapest returns the cheapest priced printing, or listed when none is cheaper.
+// Digital printings are only considered for MTGO tickets.
+func cheapest(candidates []scryfall.Card, listed scryfall.Card, currency Currency, finish string) scryfall.Card {
+	best := listed
+	bestPrice, found := cardPrice(listed.Prices, currency, finish)
+	for _, candidate := range candidates {
+		if candidate.Digital && currency != Tix {
+			continue
+		}
+		amount, ok := cardPrice(candidate.Prices, currency, finish)
+		if ok && (!found || amount < bestPrice) {
+			best, bestPrice, found = candidate, amount, true
+		}
+	}
+	return best
+}
+
+// WriteText writes per-card prices with section subtotals and the deck total
+func (r *Report) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("%s: prices in %s using the %s printing\n", r.Deck, r.Currency, r.Mode)
+	for _, section := range r.Sections {
+		printf("\n%s\n", section.Name)
+		for _, card := range r.Cards {
+			if card.Section != section.Name {
+				continue
+			}
+			name := card.Name
+			if card.Set != "" {
+				name = fmt.Sprintf("%s (%s %s)", card.Name, card.Set, card.CollectorNumber)
+			}
+			if card.Finish != "" && card.Finish != model.FinishNonfoil {
+				name += " " + card.Finish
+			}
+			if card.Missing {
+				printf("  %3d  %-48s %12s\n", card.Quantity, name, "no price")
+				continue
+			}
+			printf("  %3d  %-48s %12s %12s\n", card.Quantity, name, r.Currency.Format(card.Each), r.Currency.Format(card.Total))
+		}
+		printf("       %-48s %12s %12s\n", "Subtotal", "", r.Currency.Format(section.Total))
+	}
+
+	printf("\nTotal: %s", r.Currency.Format(r.Total))
+	if r.Missing > 0 {
+		printf(" (%d line(s) without a price)", r.Missing)
+	}
+	printf("\n")
+	return err
+}
@@ -0,0 +1,176 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/mattn/go-isatty"
+	"github.com/schollz/progressbar/v3"
+)
+
+// isTerminal reports whether a file is an interactive terminal; replaced in tests
+var isTerminal = func(file *os.File) bool {
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// NewTextReporter creates a live progress bar when stdout is a terminal and
+// falls back to indexed line output otherwise (pipes, CI logs, files)
+func NewTextReporter() Reporter {
+	if isTerminal(os.Stdout) {
+		return NewBarReporter(os.Stdout)
+	}
+	return NewProgressReporter()
+}
+
+// BarReporter implements Reporter as a live progress bar per stage showing
+// throughput, ETA, cache hits versus downloads and an error counter.
+// It is safe for concurrent use.
+type BarReporter struct {
+	mu         sync.Mutex
+	out        io.Writer
+	bar        *progressbar.ProgressBar
+	stage      Stage
+	status     string
+	cached     int
+	downloaded int
+	errors     []string
+}
+
+// stageLabels are shown while a stage's bar is running
+var stageLabels = map[Stage]string{
+	StageResolve: "Fetching cards",
+	StageRender:  "Rendering cards",
+	StageWrite:   "Writing output",
+}
+
+// NewBarReporter creates a progress bar reporter writing to out
+func NewBarReporter(out io.Writer) Reporter {
+	return &BarReporter{out: out, errors: make([]string, 0)}
+}
+
+// StartUnified begins progress tracking for a new run
+func (br *BarReporter) StartUnified(description string, total int) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.cached, br.downloaded = 0, 0
+	br.errors = make([]string, 0)
+	br.status = description
+}
+
+// StartStage replaces the bar with a new one for the stage's operations.
+// The write stage is a single operation completed immediately.
+func (br *BarReporter) StartStage(stage Stage, total int) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.newBar(stage, total)
+	if stage == StageWrite {
+		br.advance(stage, stageLabels[stage])
+	}
+}
+
+// CardFetched advances the resolve bar
+func (br *BarReporter) CardFetched(cardID, cardName string) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.advance(StageResolve, fmt.Sprintf("Fetched %s", cardName))
+}
+
+// ImageLoaded counts cache hits and downloads
+func (br *BarReporter) ImageLoaded(cardID, faceName string, cached bool) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	if cached {
+		br.cached++
+	} else {
+		br.downloaded++
+	}
+	br.describe()
+}
+
+// CardRendered advances the render bar
+func (br *BarReporter) CardRendered(cardID, cardName string, pages int) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.advance(StageRender, fmt.Sprintf("Rendered %s", cardName))
+}
+
+// AddError records an error and updates the error counter. Fetch and render
+// errors complete their card's operation.
+func (br *BarReporter) AddError(category ErrorCategory, cardName string, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.errors = append(br.errors, fmt.Sprintf("%s: %v", cardName, err))
+	if stage, ok := errorStage(category); ok {
+		br.advance(stage, fmt.Sprintf("Failed %s", cardName))
+		return
+	}
+	br.describe()
+}
+
+// Finish completes the bar and shows the completion message
+func (br *BarReporter) Finish(summary model.Summary, status Status, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.finishBar()
+	printFinish(summary, status, br.errors)
+}
+
+// newBar finishes the current bar and starts one for a stage
+func (br *BarReporter) newBar(stage Stage, total int) {
+	br.finishBar()
+	br.stage = stage
+	br.status = stageLabels[stage]
+	br.bar = progressbar.NewOptions(total,
+		progressbar.OptionSetWriter(br.out),
+		progressbar.OptionSetWidth(30),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionSetItsString("ops"),
+		progressbar.OptionSetPredictTime(true),
+		progressbar.OptionThrottle(100*time.Millisecond),
+		progressbar.OptionSetRenderBlankState(true),
+		progressbar.OptionShowDescriptionAtLineEnd(),
+		progressbar.OptionSetDescription(br.description()),
+	)
+}
+
+// finishBar completes the current bar, leaving it on its own line
+func (br *BarReporter) finishBar() {
+	if br.bar == nil {
+		return
+	}
+	br.bar.Finish()
+	fmt.Fprintln(br.out)
+	br.bar = nil
+}
+
+// advance completes one operation in a stage with a new status. Operations
+// for a stage that was never started get a bar of unknown length.
+func (br *BarReporter) advance(stage Stage, status string) {
+	if br.bar == nil || br.stage != stage {
+		br.newBar(stage, -1)
+	}
+	br.status = status
+	br.describe()
+	br.bar.Add(1)
+}
+
+// describe refreshes the text shown next to the bar
+func (br *BarReporter) describe() {
+	if br.bar != nil {
+		br.bar.Describe(br.description())
+	}
+}
+
+// description is the text shown next to the bar
+func (br *BarReporter) description() string {
+	text := fmt.Sprintf("%s | %d cached, %d downloaded", br.status, br.cached, br.downloaded)
+	if len(br.errors) > 0 {
+		text += fmt.Sprintf(" | %d error(s)", len(br.errors))
+	}
+	return text
+}
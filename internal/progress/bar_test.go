@@ -0,0 +1,66 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarReporter(t *testing.T) {
+	t.Run("counts cache hits, downloads and errors", func(t *testing.T) {
+		var out bytes.Buffer
+		reporter := NewBarReporter(&out).(*BarReporter)
+		reporter.StartUnified("Starting generation", 5)
+
+		reporter.StartStage(StageResolve, 2)
+		reporter.CardFetched("bolt", "Lightning Bolt")
+		reporter.ImageLoaded("bolt", "Lightning Bolt", true)
+		reporter.ImageLoaded("delver", "Delver of Secrets", false)
+		reporter.ImageLoaded("delver", "Insectile Aberration", false)
+		require.Equal(t, "Fetched Lightning Bolt | 1 cached, 2 downloaded", reporter.description())
+
+		reporter.AddError(CategoryFetch, "missing", errors.New("Error: 404 Not Found"))
+		require.Equal(t, "Failed missing | 1 cached, 2 downloaded | 1 error(s)", reporter.description())
+		require.EqualValues(t, 2, reporter.bar.State().CurrentNum)
+		require.Contains(t, out.String(), "ops")
+	})
+
+	t.Run("each stage gets its own bar", func(t *testing.T) {
+		var out bytes.Buffer
+		reporter := NewBarReporter(&out).(*BarReporter)
+		reporter.StartUnified("Starting generation", 5)
+
+		reporter.StartStage(StageResolve, 2)
+		reporter.CardFetched("a", "Card A")
+		reporter.CardFetched("b", "Card B")
+
+		reporter.StartStage(StageRender, 2)
+		require.Equal(t, StageRender, reporter.stage)
+		require.EqualValues(t, 0, reporter.bar.State().CurrentNum)
+		require.EqualValues(t, 2, reporter.bar.GetMax())
+		reporter.CardRendered("a", "Card A", 1)
+		require.EqualValues(t, 1, reporter.bar.State().CurrentNum)
+	})
+
+	t.Run("operations without a stage start a bar", func(t *testing.T) {
+		var out bytes.Buffer
+		reporter := NewBarReporter(&out).(*BarReporter)
+		reporter.CardFetched("bolt", "Lightning Bolt")
+		require.Equal(t, StageResolve, reporter.stage)
+		require.EqualValues(t, 1, reporter.bar.State().CurrentNum)
+	})
+}
+
+func TestNewTextReporter(t *testing.T) {
+	original := isTerminal
+	t.Cleanup(func() { isTerminal = original })
+
+	isTerminal = func(*os.File) bool { return true }
+	require.IsType(t, &BarReporter{}, NewTextReporter())
+
+	isTerminal = func(*os.File) bool { return false }
+	require.IsType(t, &ProgressReporter{}, NewTextReporter())
+}
@@ -0,0 +1,129 @@
+package progress
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Event types written by JSONReporter
+const (
+	EventStart           = "start"
+	EventStageStart      = "stage_start"
+	EventCardFetched     = "card_fetched"
+	EventImageCached     = "image_cached"
+	EventImageDownloaded = "image_downloaded"
+	EventCardRendered    = "card_rendered"
+	EventError           = "error"
+	EventFinished        = "finished"
+)
+
+// Event is one line of the JSON progress stream
+type Event struct {
+	Type     string        `json:"type"`
+	Time     time.Time     `json:"time"`
+	Message  string        `json:"message,omitempty"`
+	Stage    Stage         `json:"stage,omitempty"`
+	Total    int           `json:"total,omitempty"`
+	CardID   string        `json:"card_id,omitempty"`
+	CardName string        `json:"card_name,omitempty"`
+	Face     string        `json:"face,omitempty"`
+	Pages    int           `json:"pages,omitempty"`
+	Category ErrorCategory `json:"category,omitempty"`
+	Kind     failure.Kind  `json:"kind,omitempty"`
+
+	// Set on the finished event, with the run's error as the message
+	Status  Status         `json:"status,omitempty"`
+	Summary *model.Summary `json:"summary,omitempty"`
+	Errors  int            `json:"errors,omitempty"`
+}
+
+// JSONReporter implements Reporter as newline-delimited JSON events, for
+// scripts and GUI wrappers that consume progress programmatically.
+// It is safe for concurrent use.
+type JSONReporter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	now    func() time.Time
+	errors int
+}
+
+// NewJSONReporter creates a reporter that writes one JSON event per line to out
+func NewJSONReporter(out io.Writer) Reporter {
+	return &JSONReporter{out: out, now: time.Now}
+}
+
+// StartUnified emits a start event with the total operation count
+func (jr *JSONReporter) StartUnified(description string, total int) {
+	jr.mu.Lock()
+	jr.errors = 0
+	jr.mu.Unlock()
+	jr.emit(Event{Type: EventStart, Message: description, Total: total})
+}
+
+// StartStage emits a stage start event
+func (jr *JSONReporter) StartStage(stage Stage, total int) {
+	jr.emit(Event{Type: EventStageStart, Stage: stage, Total: total})
+}
+
+// CardFetched emits a card fetched event
+func (jr *JSONReporter) CardFetched(cardID, cardName string) {
+	jr.emit(Event{Type: EventCardFetched, CardID: cardID, CardName: cardName})
+}
+
+// ImageLoaded emits an image cached or downloaded event
+func (jr *JSONReporter) ImageLoaded(cardID, faceName string, cached bool) {
+	eventType := EventImageDownloaded
+	if cached {
+		eventType = EventImageCached
+	}
+	jr.emit(Event{Type: eventType, CardID: cardID, Face: faceName})
+}
+
+// CardRendered emits a card rendered event with the number of pages written
+func (jr *JSONReporter) CardRendered(cardID, cardName string, pages int) {
+	jr.emit(Event{Type: EventCardRendered, CardID: cardID, CardName: cardName, Pages: pages})
+}
+
+// AddError emits an error event with its stage category and failure kind
+func (jr *JSONReporter) AddError(category ErrorCategory, cardName string, err error) {
+	jr.mu.Lock()
+	jr.errors++
+	jr.mu.Unlock()
+	jr.emit(Event{Type: EventError, Category: category, Kind: failure.KindOf(err), CardName: cardName, Message: err.Error()})
+}
+
+// Finish emits the finished event with the status, output path and counts
+func (jr *JSONReporter) Finish(summary model.Summary, status Status, err error) {
+	jr.mu.Lock()
+	errors := jr.errors
+	jr.mu.Unlock()
+	event := Event{Type: EventFinished, Status: status, Summary: &summary, Errors: errors}
+	if err != nil {
+		event.Message = err.Error()
+	}
+	jr.emit(event)
+}
+
+// emit writes a single event line
+func (jr *JSONReporter) emit(event Event) {
+	event.Time = jr.now().UTC()
+	data, err := sonic.Marshal(event)
+	if err != nil {
+		log.Warn().Err(err).Str("event", event.Type).Msg("Failed to encode progress event")
+		return
+	}
+	data = append(data, '\n')
+
+	// Write each event in one call so concurrent events never interleave
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	if _, err := jr.out.Write(data); err != nil {
+		log.Warn().Err(err).Str("event", event.Type).Msg("Failed to write progress event")
+	}
+}
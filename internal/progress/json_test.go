@@ -0,0 +1,73 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONReporter(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewJSONReporter(&out).(*JSONReporter)
+	reporter.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	reporter.StartUnified("Starting generation", 5)
+	reporter.StartStage(StageResolve, 2)
+	reporter.CardFetched("bolt", "Lightning Bolt")
+	reporter.ImageLoaded("bolt", "Lightning Bolt", true)
+	reporter.ImageLoaded("delver", "Delver of Secrets", false)
+	reporter.AddError(CategoryFetch, "missing", failure.New(failure.NotFound, errors.New("Error: 404 Not Found")))
+	reporter.StartStage(StageRender, 1)
+	reporter.CardRendered("bolt", "Lightning Bolt", 4)
+	reporter.StartStage(StageWrite, 1)
+	reporter.Finish(model.Summary{OutputPath: "deck.pdf", Entries: 2, Resolved: 1, Copies: 5, Pages: 4}, StatusPartial, errors.New("1 of 2 card(s) had errors"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var events []Event
+	for _, line := range lines {
+		var event Event
+		require.NoError(t, sonic.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+
+	t.Run("one event per line in order", func(t *testing.T) {
+		var types []string
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+		require.Equal(t, []string{
+			EventStart, EventStageStart, EventCardFetched, EventImageCached, EventImageDownloaded,
+			EventError, EventStageStart, EventCardRendered, EventStageStart, EventFinished,
+		}, types)
+		require.Contains(t, lines[0], `"time":"2024-05-01T12:00:00Z"`)
+	})
+
+	t.Run("event details", func(t *testing.T) {
+		require.Equal(t, 5, events[0].Total)
+		require.Equal(t, StageResolve, events[1].Stage)
+		require.Equal(t, "Lightning Bolt", events[2].CardName)
+		require.Equal(t, "Delver of Secrets", events[4].Face)
+		require.Equal(t, CategoryFetch, events[5].Category)
+		require.Equal(t, failure.NotFound, events[5].Kind)
+		require.Equal(t, "Error: 404 Not Found", events[5].Message)
+		require.Equal(t, 4, events[7].Pages)
+	})
+
+	t.Run("finished event has output path and counts", func(t *testing.T) {
+		finished := events[len(events)-1]
+		require.Equal(t, 1, finished.Errors)
+		require.Equal(t, StatusPartial, finished.Status)
+		require.Equal(t, "1 of 2 card(s) had errors", finished.Message)
+		require.NotNil(t, finished.Summary)
+		require.Equal(t, "deck.pdf", finished.Summary.OutputPath)
+		require.Equal(t, 4, finished.Summary.Pages)
+		require.Contains(t, lines[len(lines)-1], `"output_path":"deck.pdf"`)
+	})
+}
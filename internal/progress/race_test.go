@@ -0,0 +1,132 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedBuffer is a bytes.Buffer safe for the concurrent writes made by
+// reporters (each event is a single Write call)
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// hammer reports workers*cards fetches, images and errors concurrently
+func hammer(reporter Reporter, workers, cards int) {
+	reporter.StartUnified("Concurrent", workers*cards)
+	reporter.StartStage(StageResolve, workers*cards)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for c := 0; c < cards; c++ {
+				id := fmt.Sprintf("card-%d-%d", w, c)
+				if c%5 == 0 {
+					reporter.AddError(CategoryFetch, id, errors.New("Error: 404 Not Found"))
+					continue
+				}
+				reporter.CardFetched(id, id)
+				reporter.ImageLoaded(id, id, c%2 == 0)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// discardStdout silences line output for the duration of a test
+func discardStdout(t *testing.T) {
+	oldStdout := os.Stdout
+	devNull, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = oldStdout
+		devNull.Close()
+	})
+}
+
+func TestReportersConcurrentUse(t *testing.T) {
+	const workers, cards = 8, 50
+
+	t.Run("progress reporter", func(t *testing.T) {
+		discardStdout(t)
+		reporter := NewProgressReporter().(*ProgressReporter)
+		hammer(reporter, workers, cards)
+
+		done, total := reporter.stages.progress(StageResolve)
+		require.Equal(t, workers*cards, done)
+		require.Equal(t, workers*cards, total)
+		require.Len(t, reporter.errors, workers*cards/5)
+	})
+
+	t.Run("progress lines are printed in counter order", func(t *testing.T) {
+		out, err := os.CreateTemp(t.TempDir(), "stdout")
+		require.NoError(t, err)
+		defer out.Close()
+		oldStdout := os.Stdout
+		os.Stdout = out
+		hammer(NewProgressReporter(), workers, cards)
+		os.Stdout = oldStdout
+
+		data, err := os.ReadFile(out.Name())
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, workers*cards)
+		for i, line := range lines {
+			require.True(t, strings.HasPrefix(line, fmt.Sprintf("[%d/%d] ", i+1, workers*cards)), line)
+		}
+	})
+
+	t.Run("bar reporter", func(t *testing.T) {
+		reporter := NewBarReporter(io.Discard).(*BarReporter)
+		hammer(reporter, workers, cards)
+
+		require.EqualValues(t, workers*cards, reporter.bar.State().CurrentNum)
+		require.Equal(t, workers*cards*4/5, reporter.cached+reporter.downloaded)
+		require.Len(t, reporter.errors, workers*cards/5)
+	})
+
+	t.Run("json reporter", func(t *testing.T) {
+		var out lockedBuffer
+		reporter := NewJSONReporter(&out)
+		hammer(reporter, workers, cards)
+		reporter.Finish(model.Summary{OutputPath: "deck.pdf"}, StatusSuccess, nil)
+
+		// Every line is a complete event
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2+workers*cards/5+2*workers*cards*4/5+1)
+		for _, line := range lines {
+			var event Event
+			require.NoError(t, sonic.Unmarshal([]byte(line), &event), line)
+		}
+
+		var finished Event
+		require.NoError(t, sonic.Unmarshal([]byte(lines[len(lines)-1]), &finished))
+		require.Equal(t, workers*cards/5, finished.Errors)
+	})
+}
@@ -3,57 +3,165 @@ package progress
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"sync"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+)
+
+// Stage is a phase of deck generation
+type Stage string
+
+// Generation stages in the order they run
+const (
+	StageResolve Stage = "resolve" // Fetching card data and images
+	StageRender  Stage = "render"  // Handing resolved cards to the renderer
+	StageWrite   Stage = "write"   // Writing the finished output
 )
 
-// Reporter handles simple indexed progress reporting during PDF generation
+// ErrorCategory classifies a reported error
+type ErrorCategory string
+
+// Error categories
+const (
+	CategoryFetch  ErrorCategory = "fetch"  // Card data could not be fetched
+	CategoryImage  ErrorCategory = "image"  // Card image could not be cached
+	CategoryRender ErrorCategory = "render" // Card could not be rendered
+)
+
+// Status is the outcome of a finished run
+type Status string
+
+// Run statuses
+const (
+	StatusSuccess Status = "success" // Every card was generated
+	StatusPartial Status = "partial" // Output was written, but some cards failed
+	StatusFailed  Status = "failed"  // The run stopped without writing output
+)
+
+// Reporter handles progress reporting during generation. Every run started
+// with StartUnified ends with Finish, whether or not it succeeded; err is the
+// error the run ended with, if any.
 type Reporter interface {
 	StartUnified(description string, total int)
-	UpdateStage(description string)
-	AddError(cardName, errorMsg string)
-	Finish(decklist interface{}, outputPath string)
+	StartStage(stage Stage, total int)
+	CardFetched(cardID, cardName string)
+	ImageLoaded(cardID, faceName string, cached bool)
+	CardRendered(cardID, cardName string, pages int)
+	AddError(category ErrorCategory, cardName string, err error)
+	Finish(summary model.Summary, status Status, err error)
 }
 
-// ProgressReporter implements Reporter with indexed display
+// ProgressReporter implements Reporter with one indexed line per operation.
+// Operations are counted per stage. It is safe for concurrent use.
 type ProgressReporter struct {
-	currentIndex    int
-	totalOperations int
-	errors          []string
+	mu     sync.Mutex
+	stages *stageCounter
+	errors []string
 }
 
 // NewProgressReporter creates a new progress reporter
 func NewProgressReporter() Reporter {
 	return &ProgressReporter{
+		stages: newStageCounter(),
 		errors: make([]string, 0),
 	}
 }
 
-// StartUnified begins unified progress tracking with total operations
+// StartUnified begins progress tracking for a new run
 func (pr *ProgressReporter) StartUnified(description string, total int) {
-	pr.totalOperations = total
-	pr.currentIndex = 0
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.stages = newStageCounter()
 	pr.errors = make([]string, 0)
 }
 
-// UpdateStage updates the current stage with indexed display
-func (pr *ProgressReporter) UpdateStage(description string) {
-	pr.currentIndex++
-	fmt.Printf("[%d/%d] %s\n", pr.currentIndex, pr.totalOperations, description)
+// StartStage begins counting a stage; the write stage is shown as its single operation
+func (pr *ProgressReporter) StartStage(stage Stage, total int) {
+	pr.mu.Lock()
+	pr.stages.start(stage, total)
+	pr.mu.Unlock()
+	if stage == StageWrite {
+		pr.operation(stage, "Writing output")
+	}
 }
 
-// AddError records an error that occurred during processing
-func (pr *ProgressReporter) AddError(cardName, errorMsg string) {
-	pr.errors = append(pr.errors, fmt.Sprintf("%s: %s", cardName, errorMsg))
+// CardFetched shows a fetched card
+func (pr *ProgressReporter) CardFetched(cardID, cardName string) {
+	pr.operation(StageResolve, fmt.Sprintf("Fetching card: %s", cardID))
+}
+
+// ImageLoaded is not shown in line output
+func (pr *ProgressReporter) ImageLoaded(cardID, faceName string, cached bool) {}
+
+// CardRendered shows a rendered card
+func (pr *ProgressReporter) CardRendered(cardID, cardName string, pages int) {
+	pr.operation(StageRender, fmt.Sprintf("Rendering card: %s", cardName))
+}
+
+// AddError records an error that occurred during processing. Fetch and render
+// errors complete their card's operation.
+func (pr *ProgressReporter) AddError(category ErrorCategory, cardName string, err error) {
+	pr.mu.Lock()
+	pr.errors = append(pr.errors, fmt.Sprintf("%s: %v", cardName, err))
+	pr.mu.Unlock()
+
+	if stage, ok := errorStage(category); ok {
+		pr.operation(stage, fmt.Sprintf("Failed: %s", cardName))
+	}
 }
 
 // Finish completes the progress reporting and shows comprehensive completion message
-func (pr *ProgressReporter) Finish(decklist interface{}, outputPath string) {
-	if len(pr.errors) == 0 {
-		fmt.Printf("\n✅ Successfully generated PDF '%s'\n", filepath.Base(outputPath))
-	} else {
-		fmt.Fprintf(os.Stderr, "\n❌ PDF generation completed with %d error(s):\n", len(pr.errors))
-		for _, err := range pr.errors {
-			fmt.Fprintf(os.Stderr, "   • %s\n", err)
-		}
+func (pr *ProgressReporter) Finish(summary model.Summary, status Status, err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	printFinish(summary, status, pr.errors)
+}
+
+// operation completes one operation in a stage and prints its indexed line.
+// The counter is advanced and the line printed under the same lock, so lines
+// come out in counter order and don't interleave.
+func (pr *ProgressReporter) operation(stage Stage, description string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	done, total := pr.stages.advance(stage)
+	fmt.Printf("[%d/%d] %s\n", done, total, description)
+}
+
+// errorStage returns the stage whose operation an error completes
+func errorStage(category ErrorCategory) (Stage, bool) {
+	switch category {
+	case CategoryFetch:
+		return StageResolve, true
+	case CategoryRender:
+		return StageRender, true
+	default:
+		return "", false
+	}
+}
+
+// printFinish shows the completion message and any collected errors. The
+// error a failed run ended with is shown by the caller.
+func printFinish(summary model.Summary, status Status, errors []string) {
+	output := outputLabel(summary.Format)
+	switch {
+	case status == StatusFailed:
+		fmt.Fprintf(os.Stderr, "\n❌ Generating %s failed with %d card error(s)\n", output, len(errors))
+	case len(errors) == 0:
+		fmt.Printf("\n✅ Successfully generated %s '%s'\n", output, summary.OutputPath)
+		fmt.Printf("   %d card(s), %d page(s)\n", summary.Copies, summary.Pages)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "\n❌ Generated %s '%s' with %d error(s):\n", output, summary.OutputPath, len(errors))
+	}
+	for _, err := range errors {
+		fmt.Fprintf(os.Stderr, "   • %s\n", err)
+	}
+}
+
+// outputLabel names the output of a format for completion messages
+func outputLabel(format string) string {
+	if format == "" {
+		return "output"
 	}
+	return format + " output"
 }
@@ -2,10 +2,12 @@ package progress
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"testing"
 
+	"github.com/daltonalley/deckforge-cli/internal/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,33 +16,76 @@ func TestProgressReporter(t *testing.T) {
 		reporter := NewProgressReporter().(*ProgressReporter)
 		require.NotNil(t, reporter)
 		require.Empty(t, reporter.errors)
-		require.Equal(t, 0, reporter.currentIndex)
-		require.Equal(t, 0, reporter.totalOperations)
+		done, total := reporter.stages.progress(StageResolve)
+		require.Equal(t, 0, done)
+		require.Equal(t, 0, total)
 	})
 
-	t.Run("start unified progress", func(t *testing.T) {
+	t.Run("start unified progress resets stages", func(t *testing.T) {
 		reporter := NewProgressReporter().(*ProgressReporter)
+		reporter.StartStage(StageResolve, 10)
+		reporter.AddError(CategoryImage, "Card1", errors.New("no image"))
+
 		reporter.StartUnified("Test Progress", 10)
-		require.Equal(t, 10, reporter.totalOperations)
-		require.Equal(t, 0, reporter.currentIndex)
+		require.Empty(t, reporter.errors)
+		done, total := reporter.stages.progress(StageResolve)
+		require.Equal(t, 0, done)
+		require.Equal(t, 0, total)
 	})
 
-	t.Run("update stage increments index", func(t *testing.T) {
+	t.Run("operations are counted per stage", func(t *testing.T) {
 		reporter := NewProgressReporter().(*ProgressReporter)
-		reporter.StartUnified("Test", 3)
+		reporter.StartUnified("Test", 5)
+		reporter.StartStage(StageResolve, 2)
+
+		reporter.CardFetched("a", "Card A")
+		reporter.AddError(CategoryFetch, "b", errors.New("Error: 404 Not Found"))
+		done, total := reporter.stages.progress(StageResolve)
+		require.Equal(t, 2, done)
+		require.Equal(t, 2, total)
+
+		// Image errors don't complete an operation
+		reporter.AddError(CategoryImage, "a", errors.New("no image"))
+		done, _ = reporter.stages.progress(StageResolve)
+		require.Equal(t, 2, done)
+
+		reporter.StartStage(StageRender, 1)
+		reporter.CardRendered("a", "Card A", 1)
+		done, total = reporter.stages.progress(StageRender)
+		require.Equal(t, 1, done)
+		require.Equal(t, 1, total)
+	})
+
+	t.Run("card events are shown as indexed lines", func(t *testing.T) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
 
-		reporter.UpdateStage("Operation 1")
-		require.Equal(t, 1, reporter.currentIndex)
+		reporter := NewProgressReporter().(*ProgressReporter)
+		reporter.StartUnified("Test", 5)
+		reporter.StartStage(StageResolve, 2)
+		reporter.CardFetched("bolt", "Lightning Bolt")
+		reporter.ImageLoaded("bolt", "Lightning Bolt", true)
+		reporter.AddError(CategoryFetch, "missing", errors.New("Error: 404 Not Found"))
+		reporter.StartStage(StageRender, 1)
+		reporter.CardRendered("bolt", "Lightning Bolt", 4)
+		reporter.StartStage(StageWrite, 1)
 
-		reporter.UpdateStage("Operation 2")
-		require.Equal(t, 2, reporter.currentIndex)
+		w.Close()
+		os.Stdout = oldStdout
+
+		output, _ := io.ReadAll(r)
+		require.Equal(t, "[1/2] Fetching card: bolt\n"+
+			"[2/2] Failed: missing\n"+
+			"[1/1] Rendering card: Lightning Bolt\n"+
+			"[1/1] Writing output\n", string(output))
 	})
 
 	t.Run("error tracking", func(t *testing.T) {
 		reporter := NewProgressReporter().(*ProgressReporter)
 		reporter.StartUnified("Test", 3)
 
-		reporter.AddError("Card1", "Network error")
+		reporter.AddError(CategoryFetch, "Card1", errors.New("Network error"))
 		require.Len(t, reporter.errors, 1)
 		require.Contains(t, reporter.errors[0], "Card1")
 		require.Contains(t, reporter.errors[0], "Network error")
@@ -56,14 +101,12 @@ func TestProgressReporter(t *testing.T) {
 		reporter.StartUnified("Test", 3)
 
 		// Simulate successful completion
-		reporter.UpdateStage("Op 1")
-		reporter.UpdateStage("Op 2")
-		reporter.UpdateStage("Op 3")
-
-		// Create a mock decklist
-		mockDecklist := struct{ Cards []interface{} }{Cards: make([]interface{}, 3)}
+		reporter.StartStage(StageResolve, 2)
+		reporter.CardFetched("a", "Card A")
+		reporter.CardFetched("b", "Card B")
+		reporter.StartStage(StageWrite, 1)
 
-		reporter.Finish(mockDecklist, "test.pdf")
+		reporter.Finish(model.Summary{Format: "sheet", OutputPath: "out/test.pdf", Entries: 3, Resolved: 3, Copies: 3, Pages: 4}, StatusSuccess, nil)
 
 		w.Close()
 		os.Stdout = oldStdout
@@ -71,8 +114,8 @@ func TestProgressReporter(t *testing.T) {
 		output, _ := io.ReadAll(r)
 		outputStr := string(output)
 
-		require.Contains(t, outputStr, "✅ Successfully generated PDF")
-		require.Contains(t, outputStr, "test.pdf")
+		require.Contains(t, outputStr, "✅ Successfully generated sheet output 'out/test.pdf'")
+		require.Contains(t, outputStr, "3 card(s), 4 page(s)")
 	})
 
 	t.Run("finish with errors", func(t *testing.T) {
@@ -83,11 +126,10 @@ func TestProgressReporter(t *testing.T) {
 		reporter := NewProgressReporter().(*ProgressReporter)
 		reporter.StartUnified("Test", 3)
 
-		reporter.AddError("Card1", "Network error")
-		reporter.AddError("Card2", "Parse error")
+		reporter.AddError(CategoryFetch, "Card1", errors.New("Network error"))
+		reporter.AddError(CategoryRender, "Card2", errors.New("Parse error"))
 
-		mockDecklist := struct{ Cards []interface{} }{Cards: make([]interface{}, 3)}
-		reporter.Finish(mockDecklist, "test.pdf")
+		reporter.Finish(model.Summary{Format: "images", OutputPath: "cards", Entries: 3, Resolved: 1, Copies: 3, Pages: 1}, StatusPartial, nil)
 
 		w.Close()
 		os.Stderr = oldStderr
@@ -95,10 +137,31 @@ func TestProgressReporter(t *testing.T) {
 		output, _ := io.ReadAll(r)
 		outputStr := string(output)
 
-		require.Contains(t, outputStr, "❌ PDF generation completed with 2 error(s)")
+		require.Contains(t, outputStr, "❌ Generated images output 'cards' with 2 error(s)")
 		require.Contains(t, outputStr, "Card1: Network error")
 		require.Contains(t, outputStr, "Card2: Parse error")
 	})
+
+	t.Run("finish after a failed run", func(t *testing.T) {
+		oldStdout, oldStderr := os.Stdout, os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stdout, os.Stderr = w, w
+
+		reporter := NewProgressReporter().(*ProgressReporter)
+		reporter.StartUnified("Test", 3)
+		reporter.AddError(CategoryFetch, "Card1", errors.New("Network error"))
+		reporter.Finish(model.Summary{Format: "zip", Entries: 3, Resolved: 2}, StatusFailed, errors.New("aborted"))
+
+		w.Close()
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+
+		output, _ := io.ReadAll(r)
+		outputStr := string(output)
+
+		require.Contains(t, outputStr, "❌ Generating zip output failed with 1 card error(s)")
+		require.Contains(t, outputStr, "Card1: Network error")
+		require.NotContains(t, outputStr, "Successfully")
+	})
 }
 
 // mockWriter captures output for testing
@@ -0,0 +1,57 @@
+package progress
+
+import "sync"
+
+// stageCount is the progress of a single stage
+type stageCount struct {
+	done  int
+	total int
+}
+
+// stageCounter counts completed operations separately for each stage, so a
+// stage's total is set once by the stage itself and can't drift. It is safe
+// for concurrent use.
+type stageCounter struct {
+	mu     sync.Mutex
+	stages map[Stage]*stageCount
+}
+
+// newStageCounter creates an empty counter
+func newStageCounter() *stageCounter {
+	return &stageCounter{stages: make(map[Stage]*stageCount)}
+}
+
+// start begins a stage with its total number of operations, restarting it if
+// it was already running
+func (c *stageCounter) start(stage Stage, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stages[stage] = &stageCount{total: total}
+}
+
+// advance completes one operation in a stage and returns the stage's progress.
+// A stage that was never started grows its total as operations complete.
+func (c *stageCounter) advance(stage Stage) (done, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count, ok := c.stages[stage]
+	if !ok {
+		count = &stageCount{}
+		c.stages[stage] = count
+	}
+	count.done++
+	if count.done > count.total {
+		count.total = count.done
+	}
+	return count.done, count.total
+}
+
+// progress returns the completed and total operations of a stage
+func (c *stageCounter) progress(stage Stage) (done, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if count, ok := c.stages[stage]; ok {
+		return count.done, count.total
+	}
+	return 0, 0
+}
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// DFCMode is how double-faced cards are printed
+type DFCMode string
+
+// DFC modes
+const (
+	DFCSeparate  DFCMode = "separate"  // Every face printed as its own card
+	DFCDuplex    DFCMode = "duplex"    // Each back printed behind its front
+	DFCChecklist DFCMode = "checklist" // Front proxy plus a text checklist insert
+)
+
+// checklistWidth is the number of characters per checklist line
+const checklistWidth = 32
+
+// ParseDFCMode parses a --dfc flag value
+func ParseDFCMode(value string) (DFCMode, error) {
+	switch mode := DFCMode(strings.ToLower(value)); mode {
+	case DFCSeparate, DFCDuplex, DFCChecklist:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown DFC mode '%s': use separate, duplex or checklist", value)
+	}
+}
+
+// ArrangeFaces applies the DFC mode to every resolved double-faced card.
+// Duplex marks the second face as the back of the first; checklist keeps the
+// front and replaces the other faces with a checklist insert describing them.
+// Meld cards, whose second face is their half of the melded card, are
+// printed back to back in duplex mode and as separate cards otherwise.
+func ArrangeFaces(decklist *model.Decklist, mode DFCMode) {
+	for i := range decklist.Cards {
+		entry := &decklist.Cards[i]
+		if len(entry.Faces) < 2 {
+			continue
+		}
+		if entry.Card.Layout == "meld" {
+			entry.Faces[1].Back = mode == DFCDuplex
+			continue
+		}
+		if !entry.Card.HasFaceImages() {
+			continue
+		}
+		switch mode {
+		case DFCDuplex:
+			entry.Faces[1].Back = true
+		case DFCChecklist:
+			entry.Faces = []model.Face{
+				entry.Faces[0],
+				{Name: entry.Card.Name + " (checklist)", Text: checklistLines(entry.Card)},
+			}
+		}
+	}
+}
+
+// checklistLines describes every face of a card for a checklist insert
+func checklistLines(card scryfall.Card) []string {
+	lines := []string{"CHECKLIST", ""}
+	for i, face := range card.CardFaces {
+		if i > 0 {
+			lines = append(lines, "", strings.Repeat("-", checklistWidth), "")
+		}
+		title := face.Name
+		if face.ManaCost != "" {
+			title += " " + face.ManaCost
+		}
+		lines = append(lines, wrap(title, checklistWidth)...)
+		lines = append(lines, wrap(face.TypeLine, checklistWidth)...)
+		for _, paragraph := range strings.Split(face.OracleText, "\n") {
+			lines = append(lines, wrap(paragraph, checklistWidth)...)
+		}
+		switch {
+		case face.Power != "" || face.Toughness != "":
+			lines = append(lines, face.Power+"/"+face.Toughness)
+		case face.Loyalty != "":
+			lines = append(lines, "Loyalty: "+face.Loyalty)
+		}
+	}
+	return lines
+}
+
+// wrap breaks text into lines of at most width characters at spaces. Words
+// longer than a line are left whole.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDFCMode(t *testing.T) {
+	mode, err := ParseDFCMode("Duplex")
+	require.NoError(t, err)
+	require.Equal(t, DFCDuplex, mode)
+
+	_, err = ParseDFCMode("both")
+	require.EqualError(t, err, "unknown DFC mode 'both': use separate, duplex or checklist")
+}
+
+func TestArrangeFaces(t *testing.T) {
+	delver := func() *model.Decklist {
+		card := scryfall.Card{Name: "Delver of Secrets // Insectile Aberration", Layout: "transform", CardFaces: []scryfall.CardFace{
+			{Name: "Delver of Secrets", ManaCost: "{U}", TypeLine: "Creature — Human Wizard",
+				OracleText: "At the beginning of your upkeep, look at the top card of your library. You may reveal that card. If an instant or sorcery card is revealed this way, transform Delver of Secrets.",
+				Power:      "1", Toughness: "1"},
+			{Name: "Insectile Aberration", TypeLine: "Creature — Human Insect", OracleText: "Flying", Power: "3", Toughness: "2"},
+		}}
+		return &model.Decklist{Cards: []model.CardEntry{
+			{Qty: 4, ID: "delver", Card: card, Faces: []model.Face{{Name: "Delver of Secrets"}, {Name: "Insectile Aberration"}}},
+			{Qty: 1, ID: "bolt", Card: scryfall.Card{Name: "Lightning Bolt"}, Faces: []model.Face{{Name: "Lightning Bolt"}}},
+		}}
+	}
+
+	t.Run("separate leaves faces alone", func(t *testing.T) {
+		decklist := delver()
+		ArrangeFaces(decklist, DFCSeparate)
+		require.False(t, decklist.Cards[0].Faces[1].Back)
+	})
+
+	t.Run("duplex prints the back behind the front", func(t *testing.T) {
+		decklist := delver()
+		ArrangeFaces(decklist, DFCDuplex)
+		require.True(t, decklist.Cards[0].Faces[1].Back)
+		require.Len(t, decklist.Cards[1].Faces, 1)
+	})
+
+	t.Run("checklist replaces the back with an insert", func(t *testing.T) {
+		decklist := delver()
+		ArrangeFaces(decklist, DFCChecklist)
+		faces := decklist.Cards[0].Faces
+		require.Len(t, faces, 2)
+		require.Equal(t, "Delver of Secrets", faces[0].Name)
+		require.Equal(t, "Delver of Secrets // Insectile Aberration (checklist)", faces[1].Name)
+		require.Contains(t, faces[1].Text, "Delver of Secrets {U}")
+		require.Contains(t, faces[1].Text, "Insectile Aberration")
+		require.Contains(t, faces[1].Text, "3/2")
+		for _, line := range faces[1].Text {
+			require.LessOrEqual(t, len([]rune(line)), checklistWidth)
+		}
+	})
+}
+
+func TestArrangeMeldFaces(t *testing.T) {
+	bruna := func() *model.Decklist {
+		return &model.Decklist{Cards: []model.CardEntry{{
+			Qty: 1, ID: "bruna", Card: scryfall.Card{Name: "Bruna, the Fading Light", Layout: "meld"},
+			Faces: []model.Face{{Name: "Bruna, the Fading Light"}, {Name: "Brisela, Voice of Nightmares (top half)"}},
+		}}}
+	}
+
+	for _, tc := range []struct {
+		mode DFCMode
+		back bool
+	}{
+		{DFCSeparate, false},
+		{DFCDuplex, true},
+		{DFCChecklist, false},
+	} {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			decklist := bruna()
+			ArrangeFaces(decklist, tc.mode)
+			faces := decklist.Cards[0].Faces
+			require.Len(t, faces, 2)
+			require.Equal(t, tc.back, faces[1].Back)
+			require.Empty(t, faces[1].Text)
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	require.Equal(t, []string{"one two", "three"}, wrap("one two three", 8))
+	require.Equal(t, []string{"unbreakable", "word"}, wrap("unbreakable word", 5))
+	require.Nil(t, wrap("", 10))
+}
@@ -0,0 +1,135 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/daltonalley/deckforge-cli/internal/pdf"
+)
+
+// Options configures a renderer. Each format uses the fields relevant to it.
+type Options struct {
+	Bleed       float64
+	CardSize    pdf.CardSize
+	PageSize    pdf.PageSize
+	Calibration pdf.Calibration
+	DPI         int
+	ImageType   string
+}
+
+// withDefaults fills in any unset options
+func (o Options) withDefaults() Options {
+	if o.CardSize == (pdf.CardSize{}) {
+		o.CardSize = pdf.CardSizeStandard
+	}
+	if o.DPI == 0 {
+		o.DPI = pdf.DefaultDPI
+	}
+	if o.ImageType == "" {
+		o.ImageType = pdf.DefaultImageFormat
+	}
+	return o
+}
+
+// New creates a renderer for a registered output format
+func New(name string, opts Options) (Renderer, error) {
+	format, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return format.New(opts.withDefaults())
+}
+
+// Format describes a registered output format
+type Format struct {
+	Name        string
+	Description string
+	Extension   string // Default output extension; empty for directory outputs
+	New         func(opts Options) (Renderer, error)
+}
+
+// formats holds every registered output format by name
+var formats = make(map[string]Format)
+
+// Register adds an output format, replacing any format with the same name
+func Register(format Format) {
+	formats[format.Name] = format
+}
+
+// Lookup finds a registered output format by name
+func Lookup(name string) (Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown output format '%s'", name)
+	}
+	return format, nil
+}
+
+// Formats returns every registered output format sorted by name
+func Formats() []Format {
+	list := make([]Format, 0, len(formats))
+	for _, format := range formats {
+		list = append(list, format)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func init() {
+	Register(Format{
+		Name:        "pdf",
+		Description: "PDF with one card per page (or a grid with --page-size)",
+		Extension:   ".pdf",
+		New: func(opts Options) (Renderer, error) {
+			return newPDFRenderer(opts, opts.PageSize), nil
+		},
+	})
+	Register(Format{
+		Name:        "sheet",
+		Description: "PDF with cards laid out in a grid on printer paper (letter unless --page-size is set)",
+		Extension:   ".pdf",
+		New: func(opts Options) (Renderer, error) {
+			pageSize := opts.PageSize
+			if !pageSize.IsSheet() {
+				pageSize = pdf.PageSizeLetter
+			}
+			return newPDFRenderer(opts, pageSize), nil
+		},
+	})
+	Register(Format{
+		Name:        "images",
+		Description: "Folder with one image per card face and a quantity manifest",
+		New: func(opts Options) (Renderer, error) {
+			return newImageRenderer(pdf.NewImageExporter(opts.Bleed), opts)
+		},
+	})
+	Register(Format{
+		Name:        "zip",
+		Description: "ZIP archive of the image folder and manifest",
+		Extension:   ".zip",
+		New: func(opts Options) (Renderer, error) {
+			return newImageRenderer(pdf.NewZipExporter(opts.Bleed), opts)
+		},
+	})
+}
+
+// newPDFRenderer configures a PDF generator for the given page size
+func newPDFRenderer(opts Options, pageSize pdf.PageSize) Renderer {
+	generator := pdf.NewGenerator(opts.Bleed)
+	generator.SetCardSize(opts.CardSize)
+	generator.SetPageSize(pageSize)
+	generator.SetCalibration(opts.Calibration)
+	return generator
+}
+
+// newImageRenderer configures an image exporter
+func newImageRenderer(exporter *pdf.ImageExporter, opts Options) (Renderer, error) {
+	exporter.SetCardSize(opts.CardSize)
+	if err := exporter.SetDPI(opts.DPI); err != nil {
+		return nil, err
+	}
+	if err := exporter.SetFormat(opts.ImageType); err != nil {
+		return nil, err
+	}
+	return exporter, nil
+}
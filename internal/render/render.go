@@ -0,0 +1,98 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/pdf"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/rs/zerolog/log"
+)
+
+// Renderer turns resolved cards into an output artifact. Cards are added one
+// at a time in decklist order so renderers can write incrementally; Finish
+// writes or closes the artifact. Renderers only read local data: card data
+// and images are fetched beforehand by the resolver.
+type Renderer interface {
+	SetOutputPath(path string)
+	Start(decklist *model.Decklist) error
+	AddCard(entry model.CardEntry) error
+	Finish() error
+}
+
+// PageRecorder is implemented by renderers that place cards on numbered pages
+type PageRecorder interface {
+	// CardPages returns the pages written by the most recent AddCard call
+	CardPages() []int
+}
+
+// TextPager is implemented by renderers that can print text: a page after
+// the cards, such as deck statistics, and text faces such as DFC checklists
+type TextPager interface {
+	SetTextPage(page pdf.TextPage)
+}
+
+// ImageQualitySelector is implemented by renderers that need source images
+// of a particular Scryfall quality, such as high-DPI image exports
+type ImageQualitySelector interface {
+	ImageQuality() string
+}
+
+// Render hands every resolved card in the decklist to the renderer.
+// Entries that failed to resolve are skipped; cards that cannot be rendered
+// are reported and skipped. Page numbers and render errors are recorded on
+// the decklist entries.
+func Render(renderer Renderer, decklist *model.Decklist, reporter progress.Reporter) error {
+	if err := renderer.Start(decklist); err != nil {
+		return err
+	}
+
+	if reporter != nil {
+		reporter.StartStage(progress.StageRender, countResolved(decklist))
+	}
+	pageRecorder, recordsPages := renderer.(PageRecorder)
+	for i := range decklist.Cards {
+		cardEntry := &decklist.Cards[i]
+		if !cardEntry.Resolved() {
+			// Resolution failures were already reported by the resolver
+			continue
+		}
+
+		err := renderer.AddCard(*cardEntry)
+		if recordsPages {
+			cardEntry.Pages = pageRecorder.CardPages()
+		}
+		if err != nil {
+			cardEntry.Err = failure.New(failure.Render, err)
+			log.Error().Err(err).Str("cardID", cardEntry.ID).Msg("Failed to render card")
+			if reporter != nil {
+				reporter.AddError(progress.CategoryRender, fmt.Sprintf("%s (%s)", cardEntry.Card.Name, cardEntry.ID), cardEntry.Err)
+			}
+			continue
+		}
+		if reporter != nil {
+			reporter.CardRendered(cardEntry.ID, cardEntry.Card.Name, len(cardEntry.Faces)*cardEntry.Qty)
+		}
+	}
+
+	// Write the finished artifact
+	if reporter != nil {
+		reporter.StartStage(progress.StageWrite, 1)
+	}
+	if err := renderer.Finish(); err != nil {
+		return failure.New(failure.Render, err)
+	}
+	return nil
+}
+
+// countResolved counts the entries that will be rendered
+func countResolved(decklist *model.Decklist) int {
+	count := 0
+	for _, entry := range decklist.Cards {
+		if entry.Resolved() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,165 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRenderer records the calls made by the render driver
+type fakeRenderer struct {
+	outputPath string
+	started    bool
+	cards      []string
+	failOn     string
+	finished   bool
+}
+
+func (f *fakeRenderer) SetOutputPath(path string) { f.outputPath = path }
+
+func (f *fakeRenderer) Start(decklist *model.Decklist) error {
+	f.started = true
+	return nil
+}
+
+func (f *fakeRenderer) AddCard(entry model.CardEntry) error {
+	if entry.ID == f.failOn {
+		return errors.New("render failed")
+	}
+	f.cards = append(f.cards, entry.ID)
+	return nil
+}
+
+// CardPages pretends each card fills one page
+func (f *fakeRenderer) CardPages() []int { return []int{len(f.cards)} }
+
+func (f *fakeRenderer) Finish() error {
+	f.finished = true
+	return nil
+}
+
+// fakeReporter records progress calls
+type fakeReporter struct {
+	events []string
+	errors []string
+}
+
+func (r *fakeReporter) StartUnified(description string, total int) {}
+func (r *fakeReporter) StartStage(stage progress.Stage, total int) {
+	r.events = append(r.events, fmt.Sprintf("stage %s %d", stage, total))
+}
+func (r *fakeReporter) CardFetched(cardID, cardName string) {
+	r.events = append(r.events, "fetched "+cardID)
+}
+func (r *fakeReporter) ImageLoaded(cardID, faceName string, cached bool) {
+	r.events = append(r.events, fmt.Sprintf("image %s cached=%t", faceName, cached))
+}
+func (r *fakeReporter) CardRendered(cardID, cardName string, pages int) {
+	r.events = append(r.events, fmt.Sprintf("rendered %s %d", cardID, pages))
+}
+func (r *fakeReporter) AddError(category progress.ErrorCategory, cardName string, err error) {
+	r.errors = append(r.errors, fmt.Sprintf("%s %s: %v", category, cardName, err))
+}
+func (r *fakeReporter) Finish(summary model.Summary, status progress.Status, err error) {}
+
+// resolvedEntry builds a decklist entry as the resolver would leave it
+func resolvedEntry(qty int, id string) model.CardEntry {
+	name := "Card " + id
+	return model.CardEntry{
+		Qty:   qty,
+		ID:    id,
+		Card:  scryfall.Card{ID: id, Name: name},
+		Faces: []model.Face{{Name: name}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		resolvedEntry(1, "a"),
+		{Qty: 2, ID: "missing"}, // Failed to resolve
+		resolvedEntry(1, "broken"),
+		resolvedEntry(4, "b"),
+	}}
+
+	t.Run("skips unresolved and failed cards and finishes", func(t *testing.T) {
+		renderer := &fakeRenderer{failOn: "broken"}
+		reporter := &fakeReporter{}
+
+		require.NoError(t, Render(renderer, decklist, reporter))
+		require.True(t, renderer.started)
+		require.True(t, renderer.finished)
+		require.Equal(t, []string{"a", "b"}, renderer.cards)
+
+		require.Equal(t, []string{"render Card broken (broken): render failed"}, reporter.errors)
+		require.Equal(t, []int{1}, decklist.Cards[0].Pages)
+		require.Equal(t, []int{2}, decklist.Cards[3].Pages)
+		require.EqualError(t, decklist.Cards[2].Err, "render failed")
+		require.Equal(t, failure.Render, failure.KindOf(decklist.Cards[2].Err))
+		require.Equal(t, []string{
+			"stage render 3",
+			"rendered a 1",
+			"rendered b 4",
+			"stage write 1",
+		}, reporter.events)
+	})
+
+	t.Run("works without a reporter", func(t *testing.T) {
+		renderer := &fakeRenderer{}
+		require.NoError(t, Render(renderer, decklist, nil))
+		require.Len(t, renderer.cards, 3)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("built-in formats", func(t *testing.T) {
+		var names []string
+		for _, format := range Formats() {
+			names = append(names, format.Name)
+		}
+		require.Equal(t, []string{"images", "pdf", "sheet", "zip"}, names)
+	})
+
+	t.Run("default extensions", func(t *testing.T) {
+		format, err := Lookup("zip")
+		require.NoError(t, err)
+		require.Equal(t, ".zip", format.Extension)
+
+		format, err = Lookup("images")
+		require.NoError(t, err)
+		require.Empty(t, format.Extension)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := New("docx", Options{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown output format")
+	})
+
+	t.Run("renderers are created with defaults", func(t *testing.T) {
+		for _, format := range Formats() {
+			renderer, err := New(format.Name, Options{})
+			require.NoError(t, err, format.Name)
+			require.NotNil(t, renderer)
+		}
+	})
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		_, err := New("images", Options{ImageType: "gif"})
+		require.Error(t, err)
+	})
+
+	t.Run("custom formats can be registered", func(t *testing.T) {
+		Register(Format{Name: "test", New: func(opts Options) (Renderer, error) { return &fakeRenderer{}, nil }})
+		t.Cleanup(func() { delete(formats, "test") })
+
+		renderer, err := New("test", Options{})
+		require.NoError(t, err)
+		require.IsType(t, &fakeRenderer{}, renderer)
+	})
+}
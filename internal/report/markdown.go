@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Markdown renders the report as a Markdown document
+func (r *Report) Markdown() string {
+	var b strings.Builder
+
+	title := r.Deck
+	if title == "" {
+		title = "Deck"
+	}
+	fmt.Fprintf(&b, "# DeckForge Report: %s\n\n", title)
+	if r.Source != "" {
+		fmt.Fprintf(&b, "- **Decklist**: %s\n", r.Source)
+	}
+	if r.Summary.OutputPath != "" {
+		fmt.Fprintf(&b, "- **Output**: %s (%s)\n", r.Summary.OutputPath, r.Summary.Format)
+	}
+	fmt.Fprintf(&b, "- **Generated**: %s\n", r.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Cards**: %d of %d lines resolved, %d copies, %d pages\n",
+		r.Summary.Resolved, r.Summary.Entries, r.Summary.Copies, r.Summary.Pages)
+	fmt.Fprintf(&b, "- **Errors**: %d, **Warnings**: %d\n\n", r.Errors, r.Warnings)
+
+	b.WriteString("| Line | Qty | Card | Section | Set | # | Finish | Image | Pages | Status |\n")
+	b.WriteString("|-----:|----:|------|---------|-----|---|--------|-------|-------|--------|\n")
+	for _, card := range r.Cards {
+		fmt.Fprintf(&b, "| %s | %d | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			formatLine(card.Line), card.Quantity, escapeCell(card.Name), escapeCell(card.Section),
+			printingSet(card), card.CollectorNumber, card.Finish,
+			imageSources(card.Faces), formatPages(card.Pages), card.Status)
+	}
+
+	writeNotes(&b, "Errors", r.Cards, func(card Card) []string { return card.Errors })
+	writeNotes(&b, "Warnings", r.Cards, func(card Card) []string { return card.Warnings })
+	return b.String()
+}
+
+// writeNotes lists the errors or warnings of every card under a heading
+func writeNotes(b *strings.Builder, heading string, cards []Card, notes func(Card) []string) {
+	started := false
+	for _, card := range cards {
+		for _, note := range notes(card) {
+			if !started {
+				fmt.Fprintf(b, "\n## %s\n\n", heading)
+				started = true
+			}
+			if card.Line == 0 {
+				fmt.Fprintf(b, "- %s: %s\n", card.Name, note)
+				continue
+			}
+			fmt.Fprintf(b, "- Line %d, %s: %s\n", card.Line, card.Name, note)
+		}
+	}
+}
+
+// formatLine renders a decklist line number, blank for generated entries
+func formatLine(line int) string {
+	if line == 0 {
+		return ""
+	}
+	return strconv.Itoa(line)
+}
+
+// printingSet names the printing's set, with its language when not English
+func printingSet(card Card) string {
+	set := strings.ToUpper(card.Set)
+	if card.Lang != "" && card.Lang != "en" {
+		set += " (" + strings.ToUpper(card.Lang) + ")"
+	}
+	return set
+}
+
+// imageSources summarizes where each face's image came from
+func imageSources(faces []Face) string {
+	sources := make([]string, len(faces))
+	for i, face := range faces {
+		sources[i] = face.ImageSource
+	}
+	return strings.Join(sources, " / ")
+}
+
+// formatPages compacts sorted page numbers into ranges such as "1-4, 9"
+func formatPages(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(pages[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escapeCell keeps a value from breaking the Markdown table
+func escapeCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
@@ -0,0 +1,183 @@
+// Package report writes a per-card summary of a generation run, for checking
+// exactly which printings, images and pages ended up in the output.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+)
+
+// Image sources recorded for each face
+const (
+	SourceCache    = "cache"
+	SourceDownload = "download"
+	SourceMissing  = "missing"
+)
+
+// Card statuses
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Report is the summary of a generation run
+type Report struct {
+	Deck        string        `json:"deck"`
+	Source      string        `json:"source,omitempty"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Summary     model.Summary `json:"summary"`
+	Errors      int           `json:"errors"`
+	Warnings    int           `json:"warnings"`
+	Cards       []Card        `json:"cards"`
+}
+
+// Card is the outcome for a single decklist line
+type Card struct {
+	Line            int          `json:"line,omitempty"` // Zero for generated entries such as tokens
+	Quantity        int          `json:"quantity"`
+	Name            string       `json:"name"`
+	ID              string       `json:"id"`
+	Section         string       `json:"section"`
+	Status          string       `json:"status"`
+	ErrorKind       failure.Kind `json:"error_kind,omitempty"`
+	Set             string       `json:"set,omitempty"`
+	SetName         string       `json:"set_name,omitempty"`
+	CollectorNumber string       `json:"collector_number,omitempty"`
+	Finish          string       `json:"finish,omitempty"`
+	Lang            string       `json:"lang,omitempty"`           // Language of the printed card
+	RequestedLang   string       `json:"requested_lang,omitempty"` // Language asked for by the line or --lang
+	Faces           []Face       `json:"faces,omitempty"`
+	Pages           []int        `json:"pages,omitempty"`
+	Errors          []string     `json:"errors,omitempty"`
+	Warnings        []string     `json:"warnings,omitempty"`
+}
+
+// Face records where a face's image came from
+type Face struct {
+	Name        string `json:"name"`
+	ImageSource string `json:"image_source"`
+	ImagePath   string `json:"image_path,omitempty"`
+}
+
+// New builds a report from a decklist after resolution and rendering
+func New(decklist *model.Decklist, summary model.Summary, generatedAt time.Time) *Report {
+	report := &Report{
+		Deck:        decklist.Name,
+		Source:      decklist.Source,
+		GeneratedAt: generatedAt,
+		Summary:     summary,
+		Cards:       make([]Card, 0, len(decklist.Cards)),
+	}
+	for _, entry := range decklist.Cards {
+		card := newCard(entry)
+		report.Errors += len(card.Errors)
+		report.Warnings += len(card.Warnings)
+		report.Cards = append(report.Cards, card)
+	}
+	return report
+}
+
+// newCard builds the report line for a decklist entry
+func newCard(entry model.CardEntry) Card {
+	card := Card{
+		Line:            entry.Line,
+		Quantity:        entry.Qty,
+		Name:            entry.DisplayName(),
+		ID:              entry.ID,
+		Section:         entry.SectionName(),
+		Status:          StatusOK,
+		Set:             entry.Card.Set,
+		SetName:         entry.Card.SetName,
+		CollectorNumber: entry.Card.CollectorNumber,
+		Finish:          entry.Finish,
+		Lang:            entry.Card.Lang,
+		RequestedLang:   entry.Lang,
+		Pages:           entry.Pages,
+	}
+
+	if entry.Err != nil {
+		card.Status = StatusFailed
+		card.ErrorKind = failure.KindOf(entry.Err)
+		card.Errors = append(card.Errors, entry.Err.Error())
+	} else if !entry.Resolved() {
+		card.Status = StatusFailed
+		card.Errors = append(card.Errors, "card was not resolved")
+	}
+
+	for _, face := range entry.Faces {
+		source := SourceDownload
+		switch {
+		case face.Err != nil:
+			source = SourceMissing
+			card.Warnings = append(card.Warnings, fmt.Sprintf("%s: printed without art: %v", face.Name, face.Err))
+		case face.Cached:
+			source = SourceCache
+		}
+		card.Faces = append(card.Faces, Face{Name: face.Name, ImageSource: source, ImagePath: face.ImagePath})
+	}
+
+	if entry.Resolved() {
+		card.Warnings = append(card.Warnings, printingWarnings(entry)...)
+	}
+	return card
+}
+
+// printingWarnings flags resolved printings that may not print well
+func printingWarnings(entry model.CardEntry) []string {
+	var warnings []string
+	switch entry.Card.ImageStatus {
+	case "placeholder", "missing":
+		warnings = append(warnings, fmt.Sprintf("Scryfall image is a %s", entry.Card.ImageStatus))
+	case "lowres":
+		warnings = append(warnings, "Scryfall image is low resolution")
+	}
+	if entry.Card.Digital {
+		warnings = append(warnings, "printing is digital-only")
+	}
+	if entry.Lang != "" && entry.Card.Lang != "" && entry.Card.Lang != entry.Lang {
+		warnings = append(warnings, fmt.Sprintf("no %s printing found, printed in %s", entry.Lang, entry.Card.Lang))
+	}
+	return warnings
+}
+
+// CheckPath reports whether a report can be written in the format implied
+// by the path's extension
+func CheckPath(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".md", ".markdown":
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format '%s': use .json or .md", filepath.Ext(path))
+	}
+}
+
+// Write saves the report to path as JSON (.json) or Markdown (.md)
+func (r *Report) Write(path string) error {
+	if err := CheckPath(path); err != nil {
+		return err
+	}
+
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		encoded, err := sonic.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		data = append(encoded, '\n')
+	default:
+		data = []byte(r.Markdown())
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// testDecklist has a cached card, a downloaded double-faced card, a card
+// without art and a card that failed to resolve
+func testDecklist() *model.Decklist {
+	return &model.Decklist{Name: "Delver", Source: "decks/delver.csv", Cards: []model.CardEntry{
+		{
+			Line: 1, Qty: 4, ID: "bolt", Finish: model.FinishFoil,
+			Card:  scryfall.Card{Name: "Lightning Bolt", Set: "m11", SetName: "Magic 2011", CollectorNumber: "149"},
+			Faces: []model.Face{{Name: "Lightning Bolt", ImagePath: ".card_cache/bolt.jpg", Cached: true}},
+			Pages: []int{1, 2, 3, 4},
+		},
+		{
+			Line: 2, Qty: 1, ID: "delver", Section: "Sideboard",
+			Card: scryfall.Card{Name: "Delver of Secrets // Insectile Aberration", Set: "isd", CollectorNumber: "51"},
+			Faces: []model.Face{
+				{Name: "Delver of Secrets", ImagePath: ".card_cache/front.jpg"},
+				{Name: "Insectile Aberration", ImagePath: ".card_cache/back.jpg"},
+			},
+			Pages: []int{5, 6},
+		},
+		{
+			Line: 3, Qty: 1, ID: "blank",
+			Card:  scryfall.Card{Name: "Blank | Card", Set: "tst", ImageStatus: "placeholder", Digital: true},
+			Faces: []model.Face{{Name: "Blank | Card", Err: errors.New("no image")}},
+			Pages: []int{7},
+		},
+		{Line: 4, Qty: 2, ID: "missing", Name: "Missing Card", Err: failure.New(failure.NotFound, errors.New("Error: 404 Not Found"))},
+	}}
+}
+
+func TestLanguage(t *testing.T) {
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Qty: 1, ID: "bolt", Lang: "ja", Card: scryfall.Card{Name: "Lightning Bolt", Set: "sta", Lang: "ja"},
+			Faces: []model.Face{{Name: "Lightning Bolt"}}},
+		{Qty: 1, ID: "ring", Lang: "de", Card: scryfall.Card{Name: "Sol Ring", Set: "c21", Lang: "en"},
+			Faces: []model.Face{{Name: "Sol Ring"}}},
+	}}
+	report := New(decklist, model.NewSummary(decklist, "pdf", "deck.pdf"), time.Now())
+
+	t.Run("records the printed and requested language", func(t *testing.T) {
+		require.Equal(t, "ja", report.Cards[0].Lang)
+		require.Equal(t, "ja", report.Cards[0].RequestedLang)
+		require.Empty(t, report.Cards[0].Warnings)
+	})
+
+	t.Run("warns about an English fallback", func(t *testing.T) {
+		require.Equal(t, []string{"no de printing found, printed in en"}, report.Cards[1].Warnings)
+	})
+
+	t.Run("markdown shows non-English printings", func(t *testing.T) {
+		markdown := report.Markdown()
+		require.Contains(t, markdown, "| STA (JA) |")
+		require.Contains(t, markdown, "| C21 |")
+	})
+}
+
+func TestNew(t *testing.T) {
+	decklist := testDecklist()
+	summary := model.NewSummary(decklist, "pdf", "delver.pdf")
+	report := New(decklist, summary, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	require.Len(t, report.Cards, 4)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, 3, report.Warnings)
+
+	t.Run("resolved printing and pages", func(t *testing.T) {
+		bolt := report.Cards[0]
+		require.Equal(t, 1, bolt.Line)
+		require.Equal(t, StatusOK, bolt.Status)
+		require.Equal(t, "m11", bolt.Set)
+		require.Equal(t, "149", bolt.CollectorNumber)
+		require.Equal(t, model.FinishFoil, bolt.Finish)
+		require.Equal(t, model.MainSection, bolt.Section)
+		require.Equal(t, []int{1, 2, 3, 4}, bolt.Pages)
+	})
+
+	t.Run("image sources", func(t *testing.T) {
+		require.Equal(t, SourceCache, report.Cards[0].Faces[0].ImageSource)
+		require.Equal(t, SourceDownload, report.Cards[1].Faces[1].ImageSource)
+		require.Equal(t, SourceMissing, report.Cards[2].Faces[0].ImageSource)
+	})
+
+	t.Run("errors and warnings", func(t *testing.T) {
+		require.Equal(t, []string{
+			"Blank | Card: printed without art: no image",
+			"Scryfall image is a placeholder",
+			"printing is digital-only",
+		}, report.Cards[2].Warnings)
+
+		missing := report.Cards[3]
+		require.Equal(t, StatusFailed, missing.Status)
+		require.Equal(t, "Missing Card", missing.Name)
+		require.Equal(t, []string{"Error: 404 Not Found"}, missing.Errors)
+		require.Equal(t, failure.NotFound, missing.ErrorKind)
+		require.Empty(t, missing.Warnings)
+	})
+}
+
+func TestTokens(t *testing.T) {
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Line: 2, Qty: 1, ID: "pyromancer", Card: scryfall.Card{Name: "Young Pyromancer", Set: "m14"},
+			Faces: []model.Face{{Name: "Young Pyromancer"}}},
+		{Qty: 1, ID: "elemental", Name: "Elemental", Section: "Tokens",
+			Err: failure.New(failure.NotFound, errors.New("Error: 404 Not Found"))},
+	}}
+	report := New(decklist, model.NewSummary(decklist, "pdf", "deck.pdf"), time.Now())
+
+	t.Run("keeps decklist line numbers", func(t *testing.T) {
+		require.Equal(t, 2, report.Cards[0].Line)
+	})
+
+	t.Run("leaves generated entries without a line", func(t *testing.T) {
+		require.Zero(t, report.Cards[1].Line)
+
+		data, err := sonic.Marshal(report.Cards[1])
+		require.NoError(t, err)
+		require.NotContains(t, string(data), `"line"`)
+	})
+
+	t.Run("markdown leaves the line blank", func(t *testing.T) {
+		markdown := report.Markdown()
+		require.Contains(t, markdown, "|  | 1 | Elemental | Tokens |")
+		require.Contains(t, markdown, "## Errors\n\n- Elemental: Error: 404 Not Found")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	decklist := testDecklist()
+	report := New(decklist, model.NewSummary(decklist, "pdf", "delver.pdf"), time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "report.json")
+		require.NoError(t, report.Write(path))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var decoded Report
+		require.NoError(t, sonic.Unmarshal(data, &decoded))
+		require.Equal(t, "delver.pdf", decoded.Summary.OutputPath)
+		require.Equal(t, "isd", decoded.Cards[1].Set)
+		require.Equal(t, []int{5, 6}, decoded.Cards[1].Pages)
+		require.Contains(t, string(data), `"image_source": "cache"`)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		path := filepath.Join(dir, "report.md")
+		require.NoError(t, report.Write(path))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		markdown := string(data)
+		require.Contains(t, markdown, "# DeckForge Report: Delver")
+		require.Contains(t, markdown, "| 1 | 4 | Lightning Bolt | Mainboard | M11 | 149 | foil | cache | 1-4 | ok |")
+		require.Contains(t, markdown, "| download / download | 5-6 | ok |")
+		require.Contains(t, markdown, "Blank \\| Card")
+		require.Contains(t, markdown, "## Errors\n\n- Line 4, Missing Card: Error: 404 Not Found")
+		require.Contains(t, markdown, "## Warnings")
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		err := report.Write(filepath.Join(dir, "report.txt"))
+		require.EqualError(t, err, "unsupported report format '.txt': use .json or .md")
+	})
+}
+
+func TestFormatPages(t *testing.T) {
+	require.Equal(t, "", formatPages(nil))
+	require.Equal(t, "3", formatPages([]int{3}))
+	require.Equal(t, "1-4, 9, 11-12", formatPages([]int{1, 2, 3, 4, 9, 11, 12}))
+}
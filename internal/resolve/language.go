@@ -0,0 +1,46 @@
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/rs/zerolog/log"
+)
+
+// fallbackLanguage is used when no preferred language has a printing
+const fallbackLanguage = "en"
+
+// findTranslation looks for the same printing in the entry's language, then
+// each preferred language, then English. It reports false when the fetched
+// printing is already the best available or no other language exists. Only a
+// missing printing moves on to the next language; any other lookup error is
+// returned.
+func (r *Resolver) findTranslation(card scryfall.Card, lang string) (scryfall.Card, bool, error) {
+	if lang == "" || card.Set == "" || card.CollectorNumber == "" {
+		return scryfall.Card{}, false, nil
+	}
+
+	order := []string{lang}
+	for _, preferred := range append(slices.Clone(r.languages), fallbackLanguage) {
+		if !slices.Contains(order, preferred) {
+			order = append(order, preferred)
+		}
+	}
+	for _, candidate := range order {
+		if candidate == card.Lang {
+			return scryfall.Card{}, false, nil
+		}
+		translated, err := r.findCardLang(card.Set, card.CollectorNumber, candidate)
+		if errors.Is(err, scryfall.ErrNotFound) {
+			log.Debug().Err(err).Str("cardID", card.ID).Str("lang", candidate).Msg("No printing in language")
+			continue
+		}
+		if err != nil {
+			return scryfall.Card{}, false, fmt.Errorf("failed to fetch %s printing: %w", candidate, err)
+		}
+		return translated, true, nil
+	}
+	return scryfall.Card{}, false, nil
+}
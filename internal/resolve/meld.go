@@ -0,0 +1,153 @@
+package resolve
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// Halves of a meld result printed on the backs of its meld cards
+const (
+	meldTop    = "top"
+	meldBottom = "bottom"
+)
+
+// meldResult returns the melded card a meld card turns into. The result
+// itself also uses the meld layout and is printed as a normal card.
+func meldResult(card scryfall.Card) (scryfall.RelatedCard, bool) {
+	if card.Layout != "meld" {
+		return scryfall.RelatedCard{}, false
+	}
+	for _, part := range card.AllParts {
+		if part.Component == "meld_result" && part.ID != card.ID {
+			return part, true
+		}
+	}
+	return scryfall.RelatedCard{}, false
+}
+
+// meldHalf picks the half of the result on the back of a meld card. The card
+// sharing the result's collector number (e.g. 15a and 15b) carries the top
+// half; its partner carries the bottom.
+func meldHalf(card, result scryfall.Card) string {
+	if collectorBase(card.CollectorNumber) == collectorBase(result.CollectorNumber) {
+		return meldTop
+	}
+	return meldBottom
+}
+
+// collectorBase strips the face letter from a collector number
+func collectorBase(number string) string {
+	return strings.TrimRight(number, "abcdefghijklmnopqrstuvwxyz")
+}
+
+// meldFetch is a meld result fetched once per run and shared by both of its
+// meld cards, which are resolved concurrently
+type meldFetch struct {
+	once      sync.Once
+	card      scryfall.Card
+	cardErr   error
+	imagePath string
+	imageErr  error
+}
+
+// fetchMeldResult fetches the meld result card and caches its image, once
+// per run for each result
+func (r *Resolver) fetchMeldResult(uri string) *meldFetch {
+	r.meldMu.Lock()
+	if r.melds == nil {
+		r.melds = make(map[string]*meldFetch)
+	}
+	fetch, ok := r.melds[uri]
+	if !ok {
+		fetch = &meldFetch{}
+		r.melds[uri] = fetch
+	}
+	r.meldMu.Unlock()
+
+	fetch.once.Do(func() {
+		fetch.card, fetch.cardErr = r.findCardByURI(uri)
+		if fetch.cardErr != nil {
+			return
+		}
+		fetch.imagePath, _, fetch.imageErr = r.downloadImage(fetch.card.ID, fetch.card.Name, r.quality, fetch.card.ImageURIs, r.cacheDir)
+	})
+	return fetch
+}
+
+// resolveMeldBack fetches the meld result of a card and caches the card's
+// half of it as a second face. Whether the half is printed behind the card
+// is decided by the DFC mode when the deck is rendered.
+func (r *Resolver) resolveMeldBack(card scryfall.Card, part scryfall.RelatedCard) model.Face {
+	face := model.Face{Name: part.Name}
+	fetch := r.fetchMeldResult(part.URI)
+	if fetch.cardErr != nil {
+		face.Err = fmt.Errorf("failed to fetch meld result: %w", fetch.cardErr)
+		return face
+	}
+
+	result := fetch.card
+	half := meldHalf(card, result)
+	face.Name = fmt.Sprintf("%s (%s half)", result.Name, half)
+	if fetch.imageErr != nil {
+		face.Err = fetch.imageErr
+		return face
+	}
+	imagePath := fetch.imagePath
+
+	// Halves are cut from the result image, so they are cached per quality too
+	cacheKey := fmt.Sprintf("%s_%s_meld_%s", result.ID, r.quality, half)
+	face.ImagePath = scryfall.CachedImagePath(cacheKey, r.cacheDir)
+	if face.Cached = isCached(cacheKey, r.cacheDir); !face.Cached {
+		if err := cutMeldHalf(imagePath, face.ImagePath, half); err != nil {
+			face.ImagePath = ""
+			face.Err = err
+		}
+	}
+	return face
+}
+
+// cutMeldHalf crops the top or bottom half of a meld result image and turns
+// it a quarter turn clockwise so it fills a portrait card back
+func cutMeldHalf(srcPath, dstPath, half string) error {
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open meld result image: %w", err)
+	}
+	src, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode meld result image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	crop := image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+bounds.Dy()/2)
+	if half == meldBottom {
+		crop = image.Rect(bounds.Min.X, bounds.Min.Y+bounds.Dy()/2, bounds.Max.X, bounds.Max.Y)
+	}
+	piece := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(piece, piece.Bounds(), src, crop.Min, draw.Src)
+
+	rotated := image.NewRGBA(image.Rect(0, 0, crop.Dy(), crop.Dx()))
+	for y := 0; y < crop.Dy(); y++ {
+		for x := 0; x < crop.Dx(); x++ {
+			rotated.Set(crop.Dy()-1-y, x, piece.At(x, y))
+		}
+	}
+
+	err = scryfall.WriteCached(dstPath, func(w io.Writer) error {
+		return jpeg.Encode(w, rotated, &jpeg.Options{Quality: 95})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write meld half image: %w", err)
+	}
+	return nil
+}
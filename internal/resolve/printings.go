@@ -0,0 +1,52 @@
+package resolve
+
+import (
+	"sync"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/rs/zerolog/log"
+)
+
+// ResolvePrintings fetches every printing of each resolved card, keyed by
+// oracle ID. Cards whose printings cannot be fetched are left out of the map
+// and listed as failures.
+func (r *Resolver) ResolvePrintings(decklist *model.Decklist) (map[string][]scryfall.Card, []Failure) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		printings = make(map[string][]scryfall.Card)
+		failures  []Failure
+		limit     = make(chan struct{}, r.concurrency)
+		seen      = make(map[string]bool)
+	)
+
+	for _, entry := range decklist.Cards {
+		card := entry.Card
+		if !entry.Resolved() || card.OracleID == "" || card.PrintsSearchURI == "" || seen[card.OracleID] {
+			continue
+		}
+		seen[card.OracleID] = true
+
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(card scryfall.Card) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+
+			cards, err := r.searchCards(card.PrintsSearchURI)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Error().Err(err).Str("cardID", card.ID).Msg("Failed to fetch printings")
+				failures = append(failures, Failure{ID: card.ID, Name: card.Name, Err: err})
+				return
+			}
+			printings[card.OracleID] = cards
+		}(card)
+	}
+	wg.Wait()
+	return printings, failures
+}
@@ -0,0 +1,86 @@
+package resolve
+
+import (
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// TokenSection is the deck section of entries added by ResolveRelated
+const TokenSection = "Tokens"
+
+// relatedComponents are the all_parts components printed alongside a deck:
+// tokens and emblems, the other half of a meld pair, and cards a combo needs
+var relatedComponents = map[string]bool{
+	"token":       true,
+	"meld_part":   true,
+	"combo_piece": true,
+}
+
+// ResolveRelated appends an entry for every token, emblem, meld part and combo
+// piece related to a resolved card in the deck, qty copies each. Parts already
+// in the deck are skipped, and each part is added once even when several cards
+// make it. Parts are fetched by their Scryfall URI; failures are recorded on
+// the appended entries like any other card.
+func (r *Resolver) ResolveRelated(decklist *model.Decklist, qty int, reporter progress.Reporter) (*Report, error) {
+	entries, uris := relatedEntries(decklist, qty)
+	if len(entries) == 0 {
+		return &Report{}, nil
+	}
+
+	report, err := r.resolve(entries, func(entry model.CardEntry) (scryfall.Card, error) {
+		return r.findCardByURI(uris[entry.ID])
+	}, true, reporter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Different printings of the same token share an oracle ID
+	seen := make(map[string]bool)
+	for _, entry := range decklist.Cards {
+		if entry.Card.OracleID != "" {
+			seen[entry.Card.OracleID] = true
+		}
+	}
+	for _, entry := range entries {
+		if oracleID := entry.Card.OracleID; oracleID != "" {
+			if seen[oracleID] {
+				continue
+			}
+			seen[oracleID] = true
+		}
+		decklist.Cards = append(decklist.Cards, entry)
+	}
+	return report, nil
+}
+
+// relatedEntries collects the related parts of every resolved card that are
+// not already in the deck, keyed by Scryfall ID, with the URI of each part
+func relatedEntries(decklist *model.Decklist, qty int) ([]model.CardEntry, map[string]string) {
+	inDeck := make(map[string]bool)
+	for _, entry := range decklist.Cards {
+		inDeck[entry.ID] = true
+		if entry.Card.Name != "" {
+			inDeck[entry.Card.Name] = true
+		}
+	}
+
+	var entries []model.CardEntry
+	uris := make(map[string]string)
+	for _, entry := range decklist.Cards {
+		if !entry.Resolved() {
+			continue
+		}
+		for _, part := range entry.Card.AllParts {
+			if !relatedComponents[part.Component] || part.URI == "" || inDeck[part.ID] || inDeck[part.Name] {
+				continue
+			}
+			if _, ok := uris[part.ID]; ok {
+				continue
+			}
+			uris[part.ID] = part.URI
+			entries = append(entries, model.CardEntry{Qty: qty, Name: part.Name, ID: part.ID, Section: TokenSection})
+		}
+	}
+	return entries, uris
+}
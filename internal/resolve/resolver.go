@@ -0,0 +1,302 @@
+package resolve
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/rs/zerolog/log"
+)
+
+// CacheDir is where downloaded card images are kept between runs
+const CacheDir = ".card_cache"
+
+// DefaultConcurrency is the number of cards fetched in parallel
+const DefaultConcurrency = 4
+
+// Resolver fetches card data and images for every decklist entry, so that
+// renderers only ever work with local data
+type Resolver struct {
+	cacheDir    string
+	concurrency int
+	languages   []string // Preferred printing languages, in order
+	quality     string   // Scryfall image quality to download
+
+	// Meld results fetched during this run, by URI
+	meldMu sync.Mutex
+	melds  map[string]*meldFetch
+
+	// Network access, replaced in tests
+	findCard      func(id string) (scryfall.Card, error)
+	findCardByURI func(uri string) (scryfall.Card, error)
+	findCardLang  func(set, collectorNumber, lang string) (scryfall.Card, error)
+	downloadImage func(cardID, name, quality string, uris scryfall.ImageURIs, cacheDir string) (string, bool, error)
+	searchCards   func(uri string) ([]scryfall.Card, error)
+}
+
+// Failure records a card or face that could not be resolved
+type Failure struct {
+	ID   string
+	Name string
+	Err  error
+}
+
+// Report summarizes a resolution run
+type Report struct {
+	Total         int       // Number of decklist entries
+	Resolved      int       // Entries with card data and at least one face
+	Failures      []Failure // Entries whose card data could not be fetched
+	ImageFailures []Failure // Faces whose image could not be cached
+}
+
+// HasErrors reports whether any card or image failed to resolve
+func (r *Report) HasErrors() bool {
+	return len(r.Failures) > 0 || len(r.ImageFailures) > 0
+}
+
+// NewResolver creates a resolver using the default cache directory
+func NewResolver() *Resolver {
+	return &Resolver{
+		cacheDir:      CacheDir,
+		concurrency:   DefaultConcurrency,
+		quality:       scryfall.QualityNormal,
+		findCard:      scryfall.FindCardByID,
+		findCardByURI: scryfall.FindCardByURI,
+		findCardLang:  scryfall.FindCardInLanguage,
+		downloadImage: downloadImage,
+		searchCards:   scryfall.SearchCards,
+	}
+}
+
+// SetConcurrency sets how many cards are fetched in parallel
+func (r *Resolver) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	r.concurrency = n
+}
+
+// SetImageQuality sets the Scryfall image quality downloaded for each face
+func (r *Resolver) SetImageQuality(quality string) {
+	r.quality = quality
+}
+
+// SetLanguages sets the preferred printing languages, tried in order for
+// entries without a language of their own before falling back to English
+func (r *Resolver) SetLanguages(languages []string) {
+	r.languages = languages
+}
+
+// SetCacheDir sets where downloaded images are stored
+func (r *Resolver) SetCacheDir(dir string) {
+	r.cacheDir = dir
+}
+
+// Resolve populates Card and Faces for every entry in the decklist using a
+// bounded worker pool. Failed entries are left unresolved and listed in the
+// report; an error is only returned when resolution cannot start at all.
+func (r *Resolver) Resolve(decklist *model.Decklist, reporter progress.Reporter) (*Report, error) {
+	return r.resolve(decklist.Cards, r.findEntry, true, reporter)
+}
+
+// ResolveData populates Card and Faces like Resolve but downloads no images,
+// for commands that only inspect card data. Faces are listed without an
+// image path; faces Scryfall has no image for are marked with an error, as
+// Resolve would.
+func (r *Resolver) ResolveData(decklist *model.Decklist, reporter progress.Reporter) (*Report, error) {
+	return r.resolve(decklist.Cards, r.findEntry, false, reporter)
+}
+
+// findEntry fetches the card data for a decklist entry by Scryfall ID
+func (r *Resolver) findEntry(entry model.CardEntry) (scryfall.Card, error) {
+	return r.findCard(entry.ID)
+}
+
+// resolve populates the given entries, fetching each one's card data with find
+// and, when images is set, caching an image per face
+func (r *Resolver) resolve(entries []model.CardEntry, find func(entry model.CardEntry) (scryfall.Card, error), images bool, reporter progress.Reporter) (*Report, error) {
+	// Create cache directory for images
+	if images {
+		if err := os.MkdirAll(r.cacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+
+	for i := range entries {
+		if entries[i].Lang == "" && len(r.languages) > 0 {
+			entries[i].Lang = r.languages[0]
+		}
+	}
+
+	report := &Report{Total: len(entries)}
+	if reporter != nil {
+		reporter.StartStage(progress.StageResolve, len(entries))
+	}
+	for result := range r.resolveAll(entries, find, images) {
+		entry := &entries[result.index]
+
+		if result.err != nil {
+			entry.Err = result.err
+			log.Error().Err(result.err).Str("cardID", entry.ID).Msg("Failed to fetch card data")
+			if reporter != nil {
+				reporter.AddError(progress.CategoryFetch, entry.ID, result.err)
+			}
+			report.Failures = append(report.Failures, Failure{ID: entry.ID, Name: entry.Name, Err: result.err})
+			continue
+		}
+
+		entry.Card = result.card
+		entry.Faces = result.faces
+		report.Resolved++
+		if reporter != nil {
+			reporter.CardFetched(entry.ID, entry.Card.Name)
+		}
+		for _, face := range entry.Faces {
+			if face.Err != nil {
+				log.Error().Err(face.Err).Str("cardID", entry.ID).Str("cardName", face.Name).Msg("Failed to load card image")
+				if reporter != nil {
+					reporter.AddError(progress.CategoryImage, fmt.Sprintf("%s (%s)", face.Name, entry.ID), face.Err)
+				}
+				report.ImageFailures = append(report.ImageFailures, Failure{ID: entry.ID, Name: face.Name, Err: face.Err})
+				continue
+			}
+			if reporter != nil && images {
+				reporter.ImageLoaded(entry.ID, face.Name, face.Cached)
+			}
+		}
+	}
+	return report, nil
+}
+
+// result is the outcome of resolving one decklist entry
+type result struct {
+	index int
+	card  scryfall.Card
+	faces []model.Face
+	err   error
+}
+
+// resolveAll resolves entries in parallel and delivers results in decklist order
+func (r *Resolver) resolveAll(entries []model.CardEntry, find func(entry model.CardEntry) (scryfall.Card, error), images bool) <-chan result {
+	slots := make(chan chan result, r.concurrency)
+	go func() {
+		defer close(slots)
+		for i, entry := range entries {
+			slot := make(chan result, 1)
+			slots <- slot
+			go func(i int, entry model.CardEntry) {
+				slot <- r.resolveEntry(i, entry, find, images)
+			}(i, entry)
+		}
+	}()
+
+	results := make(chan result)
+	go func() {
+		defer close(results)
+		for slot := range slots {
+			results <- <-slot
+		}
+	}()
+	return results
+}
+
+// resolveEntry fetches a single card from Scryfall and, when images is set,
+// caches an image per face
+func (r *Resolver) resolveEntry(index int, entry model.CardEntry, find func(entry model.CardEntry) (scryfall.Card, error), images bool) result {
+	id := entry.ID
+	card, err := find(entry)
+	if err != nil {
+		return result{index: index, err: err}
+	}
+	translated, ok, err := r.findTranslation(card, entry.Lang)
+	if err != nil {
+		return result{index: index, err: err}
+	}
+	if ok {
+		card = translated
+		id = card.ID
+	}
+
+	res := result{index: index, card: card}
+	for _, face := range r.printedFaces(card) {
+		if !images {
+			var err error
+			if face.uris.URL(r.quality) == "" {
+				err = fmt.Errorf("no image URL available for card %s", id)
+			}
+			res.faces = append(res.faces, model.Face{Name: face.name, Err: err})
+			continue
+		}
+		imagePath, cached, err := r.downloadImage(id, face.name, r.quality, face.uris, r.cacheDir)
+		res.faces = append(res.faces, model.Face{Name: face.name, ImagePath: imagePath, Cached: cached, Err: err})
+	}
+	if part, ok := meldResult(card); ok {
+		if images {
+			res.faces = append(res.faces, r.resolveMeldBack(card, part))
+		} else {
+			res.faces = append(res.faces, model.Face{Name: part.Name})
+		}
+	}
+	return res
+}
+
+// printedFace is a face printed from its own image
+type printedFace struct {
+	name string
+	uris scryfall.ImageURIs
+}
+
+// printedFaces lists the images a card is printed from: one per face for
+// double-sided cards, otherwise a single image
+func (r *Resolver) printedFaces(card scryfall.Card) []printedFace {
+	if !card.HasFaceImages() {
+		// Split, flip, adventure and single-faced cards print as one image
+		return []printedFace{{name: card.Name, uris: card.ImageURIs}}
+	}
+	faces := make([]printedFace, 0, len(card.CardFaces))
+	for _, face := range card.CardFaces {
+		uris := face.ImageURIs
+		if uris.URL(r.quality) == "" {
+			// Some layouts keep the image on the card rather than the face
+			uris = card.ImageURIs
+		}
+		faces = append(faces, printedFace{name: face.Name, uris: uris})
+	}
+	return faces
+}
+
+// downloadImage caches the image of the given quality for a card or face and
+// reports whether it was already in the cache
+func downloadImage(cardID, name, quality string, uris scryfall.ImageURIs, cacheDir string) (string, bool, error) {
+	imageURL := uris.URL(quality)
+	if imageURL == "" {
+		// Fallback: try to get from Scryfall API
+		cached := isCached(fmt.Sprintf("%s_%s", cardID, quality), cacheDir)
+		path, err := scryfall.DownloadCardImage(cardID, cacheDir, quality)
+		return path, cached, err
+	}
+
+	cacheKey := fmt.Sprintf("%s_%s", cardID, quality)
+	if name != "" {
+		cacheKey = fmt.Sprintf("%s_%s_%s", cardID, sanitizeFilename(name), quality)
+	}
+	cached := isCached(cacheKey, cacheDir)
+	path, err := scryfall.DownloadImageFromURL(imageURL, cacheKey, cacheDir)
+	return path, cached, err
+}
+
+// isCached reports whether an image for the cache key is already on disk
+func isCached(cacheKey, cacheDir string) bool {
+	_, err := os.Stat(scryfall.CachedImagePath(cacheKey, cacheDir))
+	return err == nil
+}
+
+// sanitizeFilename creates a safe filename from card name
+func sanitizeFilename(name string) string {
+	// Simple sanitization - replace spaces and special chars
+	return strings.ReplaceAll(strings.ReplaceAll(name, " ", "_"), "/", "_")
+}
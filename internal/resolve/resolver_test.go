@@ -0,0 +1,548 @@
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/deck"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/internal/progress"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReporter records progress calls
+type fakeReporter struct {
+	events []string
+	errors []string
+}
+
+func (r *fakeReporter) StartUnified(description string, total int) {}
+func (r *fakeReporter) StartStage(stage progress.Stage, total int) {
+	r.events = append(r.events, fmt.Sprintf("stage %s %d", stage, total))
+}
+func (r *fakeReporter) CardFetched(cardID, cardName string) {
+	r.events = append(r.events, "fetched "+cardID)
+}
+func (r *fakeReporter) ImageLoaded(cardID, faceName string, cached bool) {
+	r.events = append(r.events, fmt.Sprintf("image %s cached=%t", faceName, cached))
+}
+func (r *fakeReporter) CardRendered(cardID, cardName string, pages int) {
+	r.events = append(r.events, fmt.Sprintf("rendered %s %d", cardID, pages))
+}
+func (r *fakeReporter) AddError(category progress.ErrorCategory, cardName string, err error) {
+	r.errors = append(r.errors, fmt.Sprintf("%s %s: %v", category, cardName, err))
+}
+func (r *fakeReporter) Finish(summary model.Summary, status progress.Status, err error) {}
+
+// testResolver returns a resolver backed by canned cards instead of Scryfall
+func testResolver(t *testing.T, cards map[string]scryfall.Card, downloads *atomic.Int32) *Resolver {
+	r := NewResolver()
+	r.SetCacheDir(filepath.Join(t.TempDir(), "cache"))
+	r.findCard = func(id string) (scryfall.Card, error) {
+		card, ok := cards[id]
+		if !ok {
+			return scryfall.Card{}, errors.New("Error: 404 Not Found")
+		}
+		return card, nil
+	}
+	r.downloadImage = func(cardID, name, quality string, uris scryfall.ImageURIs, cacheDir string) (string, bool, error) {
+		downloads.Add(1)
+		if uris.URL(quality) == "" {
+			return "", false, errors.New("no image")
+		}
+		// Pretend single-faced cards were downloaded on an earlier run
+		cached := name == cards[cardID].Name
+		return filepath.Join(cacheDir, cardID+"_"+sanitizeFilename(name)+".jpg"), cached, nil
+	}
+	return r
+}
+
+func TestDownloadImage(t *testing.T) {
+	t.Run("reports images already in the cache", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		uris := scryfall.ImageURIs{Normal: "https://cards.scryfall.io/normal/bolt.jpg"}
+		cachedPath := scryfall.CachedImagePath("bolt_Lightning_Bolt_normal", cacheDir)
+		require.NoError(t, os.WriteFile(cachedPath, []byte("jpeg"), 0644))
+
+		path, cached, err := downloadImage("bolt", "Lightning Bolt", scryfall.QualityNormal, uris, cacheDir)
+		require.NoError(t, err)
+		require.True(t, cached)
+		require.Equal(t, cachedPath, path)
+	})
+
+	t.Run("downloads the requested quality", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, r.URL.Path)
+		}))
+		defer server.Close()
+		cacheDir := t.TempDir()
+		uris := scryfall.ImageURIs{Normal: server.URL + "/normal", Large: server.URL + "/large", PNG: server.URL + "/png"}
+
+		path, cached, err := downloadImage("bolt", "Lightning Bolt", scryfall.QualityPNG, uris, cacheDir)
+		require.NoError(t, err)
+		require.False(t, cached)
+		require.Equal(t, filepath.Join(cacheDir, "bolt_Lightning_Bolt_png.png"), path)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "/png", string(data))
+
+		path, _, err = downloadImage("bolt", "Lightning Bolt", scryfall.QualityLarge, uris, cacheDir)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(cacheDir, "bolt_Lightning_Bolt_large.jpg"), path)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	cards := map[string]scryfall.Card{
+		"bolt": {ID: "bolt", Name: "Lightning Bolt", ImageURIs: scryfall.ImageURIs{Normal: "https://img/bolt.jpg"}},
+		"delver": {ID: "delver", Name: "Delver of Secrets // Insectile Aberration", CardFaces: []scryfall.CardFace{
+			{Name: "Delver of Secrets", ImageURIs: scryfall.ImageURIs{Normal: "https://img/front.jpg"}},
+			{Name: "Insectile Aberration", ImageURIs: scryfall.ImageURIs{Normal: "https://img/back.jpg"}},
+		}},
+		"blank": {ID: "blank", Name: "Blank Card"},
+	}
+
+	t.Run("populates cards and faces in decklist order", func(t *testing.T) {
+		var downloads atomic.Int32
+		r := testResolver(t, cards, &downloads)
+		r.SetConcurrency(2)
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			{Qty: 4, ID: "bolt"},
+			{Qty: 1, ID: "missing"},
+			{Qty: 2, ID: "delver"},
+			{Qty: 1, ID: "blank"},
+		}}
+		reporter := &fakeReporter{}
+
+		report, err := r.Resolve(decklist, reporter)
+		require.NoError(t, err)
+		require.Equal(t, 4, report.Total)
+		require.Equal(t, 3, report.Resolved)
+		require.True(t, report.HasErrors())
+		require.EqualValues(t, 4, downloads.Load())
+
+		// Resolved entries carry card data and one face per printed side
+		require.Equal(t, "Lightning Bolt", decklist.Cards[0].Card.Name)
+		require.Len(t, decklist.Cards[0].Faces, 1)
+		require.False(t, decklist.Cards[1].Resolved())
+		require.Equal(t, []string{"Delver of Secrets", "Insectile Aberration"},
+			[]string{decklist.Cards[2].Faces[0].Name, decklist.Cards[2].Faces[1].Name})
+		require.Equal(t, 10, deck.CalculateTotalPages(decklist))
+
+		// A missing image still resolves the card, without art
+		require.True(t, decklist.Cards[3].Resolved())
+		require.Error(t, decklist.Cards[3].Faces[0].Err)
+
+		require.Len(t, report.Failures, 1)
+		require.Equal(t, "missing", report.Failures[0].ID)
+		require.Len(t, report.ImageFailures, 1)
+		require.Equal(t, "Blank Card", report.ImageFailures[0].Name)
+
+		require.Equal(t, []string{
+			"stage resolve 4",
+			"fetched bolt",
+			"image Lightning Bolt cached=true",
+			"fetched delver",
+			"image Delver of Secrets cached=false",
+			"image Insectile Aberration cached=false",
+			"fetched blank",
+		}, reporter.events)
+		require.Equal(t, []string{
+			"fetch missing: Error: 404 Not Found",
+			"image Blank Card (blank): no image",
+		}, reporter.errors)
+	})
+
+	t.Run("prints faces according to the layout", func(t *testing.T) {
+		layouts := map[string]scryfall.Card{
+			"fire": {ID: "fire", Name: "Fire // Ice", Layout: "split", ImageURIs: scryfall.ImageURIs{Normal: "https://img/fire.jpg"},
+				CardFaces: []scryfall.CardFace{{Name: "Fire"}, {Name: "Ice"}}},
+			"bonecrusher": {ID: "bonecrusher", Name: "Bonecrusher Giant // Stomp", Layout: "adventure",
+				ImageURIs: scryfall.ImageURIs{Normal: "https://img/giant.jpg"},
+				CardFaces: []scryfall.CardFace{{Name: "Bonecrusher Giant"}, {Name: "Stomp"}}},
+			"reversible": {ID: "reversible", Name: "Sol Ring // Sol Ring", Layout: "reversible_card",
+				CardFaces: []scryfall.CardFace{
+					{Name: "Sol Ring", ImageURIs: scryfall.ImageURIs{Normal: "https://img/ring-front.jpg"}},
+					{Name: "Sol Ring", ImageURIs: scryfall.ImageURIs{Normal: "https://img/ring-back.jpg"}},
+				}},
+		}
+		var downloads atomic.Int32
+		r := testResolver(t, layouts, &downloads)
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			{Qty: 1, ID: "fire"}, {Qty: 1, ID: "bonecrusher"}, {Qty: 1, ID: "reversible"},
+		}}
+
+		_, err := r.Resolve(decklist, nil)
+		require.NoError(t, err)
+		require.Len(t, decklist.Cards[0].Faces, 1)
+		require.NoError(t, decklist.Cards[0].Faces[0].Err)
+		require.Len(t, decklist.Cards[1].Faces, 1)
+		require.Len(t, decklist.Cards[2].Faces, 2)
+		require.Equal(t, 4, deck.CalculateTotalPages(decklist))
+	})
+
+	t.Run("works without a reporter", func(t *testing.T) {
+		var downloads atomic.Int32
+		r := testResolver(t, cards, &downloads)
+		r.SetConcurrency(0)
+		decklist := &model.Decklist{Cards: []model.CardEntry{{Qty: 1, ID: "bolt"}}}
+
+		report, err := r.Resolve(decklist, nil)
+		require.NoError(t, err)
+		require.False(t, report.HasErrors())
+		require.True(t, decklist.Cards[0].Resolved())
+	})
+}
+
+func TestResolvePrintings(t *testing.T) {
+	var searches atomic.Int32
+	r := NewResolver()
+	r.searchCards = func(uri string) ([]scryfall.Card, error) {
+		searches.Add(1)
+		if uri == "https://search/broken" {
+			return nil, errors.New("Error: 500 Internal Server Error")
+		}
+		return []scryfall.Card{{ID: uri + "-1"}, {ID: uri + "-2"}}, nil
+	}
+	resolved := func(id, oracleID, uri string) model.CardEntry {
+		return model.CardEntry{Qty: 1, ID: id, Card: scryfall.Card{ID: id, OracleID: oracleID, PrintsSearchURI: uri},
+			Faces: []model.Face{{Name: id}}}
+	}
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		resolved("bolt-m10", "bolt", "https://search/bolt"),
+		resolved("bolt-lea", "bolt", "https://search/bolt"), // Same card, fetched once
+		resolved("broken", "broken", "https://search/broken"),
+		{Qty: 1, ID: "missing"},
+	}}
+
+	printings, failures := r.ResolvePrintings(decklist)
+	require.EqualValues(t, 2, searches.Load())
+	require.Len(t, printings, 1)
+	require.Len(t, printings["bolt"], 2)
+	require.Len(t, failures, 1)
+	require.Equal(t, "broken", failures[0].ID)
+}
+
+func TestResolveRelated(t *testing.T) {
+	part := func(id, component, name string) scryfall.RelatedCard {
+		return scryfall.RelatedCard{ID: id, Component: component, Name: name, URI: "https://api/cards/" + id}
+	}
+	cards := map[string]scryfall.Card{
+		"https://api/cards/goblin-a": {ID: "goblin-a", OracleID: "goblin", Name: "Goblin", ImageURIs: scryfall.ImageURIs{Normal: "goblin.jpg"}},
+		"https://api/cards/goblin-b": {ID: "goblin-b", OracleID: "goblin", Name: "Goblin", ImageURIs: scryfall.ImageURIs{Normal: "goblin.jpg"}},
+		"https://api/cards/emblem":   {ID: "emblem", OracleID: "emblem", Name: "Chandra Emblem", ImageURIs: scryfall.ImageURIs{Normal: "emblem.jpg"}},
+		"https://api/cards/gisela":   {ID: "gisela", OracleID: "gisela", Name: "Gisela, the Broken Blade", ImageURIs: scryfall.ImageURIs{Normal: "gisela.jpg"}},
+	}
+	var downloads atomic.Int32
+	r := testResolver(t, nil, &downloads)
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+	r.findCardByURI = func(uri string) (scryfall.Card, error) {
+		mu.Lock()
+		fetched = append(fetched, uri)
+		mu.Unlock()
+		card, ok := cards[uri]
+		if !ok {
+			return scryfall.Card{}, errors.New("Error: 404 Not Found")
+		}
+		return card, nil
+	}
+	resolved := func(id, name string, parts ...scryfall.RelatedCard) model.CardEntry {
+		return model.CardEntry{Qty: 1, ID: id, Card: scryfall.Card{ID: id, OracleID: id, Name: name, AllParts: parts},
+			Faces: []model.Face{{Name: name}}}
+	}
+
+	t.Run("appends each related part once", func(t *testing.T) {
+		fetched = nil
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			resolved("krenko", "Krenko, Mob Boss", part("krenko", "combo_piece", "Krenko, Mob Boss"), part("goblin-a", "token", "Goblin")),
+			resolved("chandra", "Chandra", part("goblin-a", "token", "Goblin"), part("emblem", "token", "Chandra Emblem")),
+			resolved("bruna", "Bruna, the Fading Light",
+				part("gisela", "meld_part", "Gisela, the Broken Blade"), part("brisela", "meld_result", "Brisela, Voice of Nightmares")),
+			resolved("horde", "Goblin Horde", part("goblin-b", "token", "Goblin"), part("lost", "token", "Lost Token")),
+			{Qty: 1, ID: "missing"},
+		}}
+
+		report, err := r.ResolveRelated(decklist, 3, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"https://api/cards/goblin-a", "https://api/cards/emblem", "https://api/cards/gisela",
+			"https://api/cards/goblin-b", "https://api/cards/lost",
+		}, fetched)
+		require.Len(t, report.Failures, 1)
+
+		added := decklist.Cards[5:]
+		var names []string
+		for _, entry := range added {
+			require.Equal(t, 3, entry.Qty)
+			require.Equal(t, TokenSection, entry.Section)
+			names = append(names, entry.Name)
+		}
+		// The second Goblin printing is the same token and is dropped
+		require.Equal(t, []string{"Goblin", "Chandra Emblem", "Gisela, the Broken Blade", "Lost Token"}, names)
+		require.Error(t, added[3].Err)
+	})
+
+	t.Run("skips parts already in the deck", func(t *testing.T) {
+		fetched = nil
+		decklist := &model.Decklist{Cards: []model.CardEntry{
+			resolved("bruna", "Bruna, the Fading Light", part("gisela", "meld_part", "Gisela, the Broken Blade")),
+			resolved("gisela-other", "Gisela, the Broken Blade", part("bruna", "meld_part", "Bruna, the Fading Light")),
+		}}
+
+		_, err := r.ResolveRelated(decklist, 1, nil)
+		require.NoError(t, err)
+		require.Empty(t, fetched)
+		require.Len(t, decklist.Cards, 2)
+	})
+}
+
+func TestResolveData(t *testing.T) {
+	meldParts := []scryfall.RelatedCard{
+		{ID: "brisela", Component: "meld_result", Name: "Brisela, Voice of Nightmares", URI: "https://api/cards/brisela"},
+	}
+	cards := map[string]scryfall.Card{
+		"bolt": {ID: "bolt", Name: "Lightning Bolt", ImageURIs: scryfall.ImageURIs{Normal: "https://img/bolt.jpg"}},
+		"delver": {ID: "delver", Name: "Delver of Secrets // Insectile Aberration", Layout: "transform", CardFaces: []scryfall.CardFace{
+			{Name: "Delver of Secrets", ImageURIs: scryfall.ImageURIs{Normal: "https://img/front.jpg"}},
+			{Name: "Insectile Aberration", ImageURIs: scryfall.ImageURIs{Normal: "https://img/back.jpg"}},
+		}},
+		"bruna": {ID: "bruna", Name: "Bruna, the Fading Light", Layout: "meld", AllParts: meldParts,
+			ImageURIs: scryfall.ImageURIs{Normal: "https://img/bruna.jpg"}},
+		"blank": {ID: "blank", Name: "Blank Card"},
+	}
+
+	var downloads atomic.Int32
+	r := testResolver(t, cards, &downloads)
+	r.findCardByURI = func(uri string) (scryfall.Card, error) {
+		t.Errorf("meld result %s fetched", uri)
+		return scryfall.Card{}, nil
+	}
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Qty: 4, ID: "bolt"}, {Qty: 1, ID: "delver"}, {Qty: 1, ID: "bruna"}, {Qty: 1, ID: "blank"}, {Qty: 1, ID: "missing"},
+	}}
+
+	report, err := r.ResolveData(decklist, nil)
+	require.NoError(t, err)
+
+	t.Run("downloads no images", func(t *testing.T) {
+		require.Zero(t, downloads.Load())
+		require.NoDirExists(t, r.cacheDir)
+		for _, entry := range decklist.Cards {
+			for _, face := range entry.Faces {
+				require.Empty(t, face.ImagePath)
+			}
+		}
+	})
+
+	t.Run("lists every printed face", func(t *testing.T) {
+		require.Equal(t, 4, report.Resolved)
+		require.Len(t, decklist.Cards[0].Faces, 1)
+		require.Len(t, decklist.Cards[1].Faces, 2)
+		require.Equal(t, "Insectile Aberration", decklist.Cards[1].Faces[1].Name)
+		require.Len(t, decklist.Cards[2].Faces, 2)
+		require.Equal(t, "Brisela, Voice of Nightmares", decklist.Cards[2].Faces[1].Name)
+	})
+
+	t.Run("faces without an image are errors", func(t *testing.T) {
+		require.False(t, decklist.Cards[0].HasErrors())
+		require.True(t, decklist.Cards[3].HasErrors())
+		require.Len(t, report.ImageFailures, 1)
+		require.Equal(t, "blank", report.ImageFailures[0].ID)
+		require.False(t, decklist.Cards[4].Resolved())
+	})
+}
+
+func TestResolveMeld(t *testing.T) {
+	// The melded card is red on top and blue below
+	resultImage := filepath.Join(t.TempDir(), "brisela.jpg")
+	img := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if y >= 30 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	out, err := os.Create(resultImage)
+	require.NoError(t, err)
+	require.NoError(t, jpeg.Encode(out, img, nil))
+	require.NoError(t, out.Close())
+
+	meldParts := []scryfall.RelatedCard{
+		{ID: "bruna", Component: "meld_part", Name: "Bruna, the Fading Light"},
+		{ID: "gisela", Component: "meld_part", Name: "Gisela, the Broken Blade"},
+		{ID: "brisela", Component: "meld_result", Name: "Brisela, Voice of Nightmares", URI: "https://api/cards/brisela"},
+	}
+	cards := map[string]scryfall.Card{
+		"bruna": {ID: "bruna", Name: "Bruna, the Fading Light", Layout: "meld", CollectorNumber: "15a",
+			AllParts: meldParts, ImageURIs: scryfall.ImageURIs{Normal: "bruna.jpg"}},
+		"gisela": {ID: "gisela", Name: "Gisela, the Broken Blade", Layout: "meld", CollectorNumber: "28a",
+			AllParts: meldParts, ImageURIs: scryfall.ImageURIs{Normal: "gisela.jpg"}},
+		"brisela": {ID: "brisela", Name: "Brisela, Voice of Nightmares", Layout: "meld", CollectorNumber: "15b",
+			AllParts: meldParts, ImageURIs: scryfall.ImageURIs{Normal: "brisela.jpg"}},
+	}
+	var downloads atomic.Int32
+	r := testResolver(t, cards, &downloads)
+	fakeDownload := r.downloadImage
+	var resultFetches, resultDownloads atomic.Int32
+	r.downloadImage = func(cardID, name, quality string, uris scryfall.ImageURIs, cacheDir string) (string, bool, error) {
+		if cardID == "brisela" {
+			resultDownloads.Add(1)
+			return resultImage, true, nil
+		}
+		return fakeDownload(cardID, name, quality, uris, cacheDir)
+	}
+	r.findCardByURI = func(uri string) (scryfall.Card, error) {
+		resultFetches.Add(1)
+		return cards[strings.TrimPrefix(uri, "https://api/cards/")], nil
+	}
+
+	// pixel decodes the color at the center of the left or right edge
+	pixel := func(path string, right bool) color.RGBA {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		img, _, err := image.Decode(file)
+		require.NoError(t, err)
+		require.Equal(t, image.Pt(30, 40), img.Bounds().Size())
+		x := 2
+		if right {
+			x = 27
+		}
+		r, g, b, _ := img.At(x, 20).RGBA()
+		return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}
+	}
+
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Qty: 1, ID: "bruna"}, {Qty: 1, ID: "gisela"}, {Qty: 1, ID: "brisela"},
+	}}
+	r.SetConcurrency(3)
+	_, err = r.Resolve(decklist, nil)
+	require.NoError(t, err)
+
+	t.Run("meld partners share one fetch of the result", func(t *testing.T) {
+		require.EqualValues(t, 1, resultFetches.Load())
+		// Once for both meld backs, once for the listed melded card itself
+		require.EqualValues(t, 2, resultDownloads.Load())
+	})
+
+	t.Run("meld cards get their half of the result as a second face", func(t *testing.T) {
+		bruna := decklist.Cards[0].Faces
+		require.Len(t, bruna, 2)
+		require.False(t, bruna[1].Back, "the DFC mode decides whether the half is a back")
+		require.NoError(t, bruna[1].Err)
+		require.Equal(t, "Brisela, Voice of Nightmares (top half)", bruna[1].Name)
+		require.Greater(t, pixel(bruna[1].ImagePath, false).R, uint8(200))
+
+		gisela := decklist.Cards[1].Faces
+		require.Len(t, gisela, 2)
+		require.Equal(t, "Brisela, Voice of Nightmares (bottom half)", gisela[1].Name)
+		require.Greater(t, pixel(gisela[1].ImagePath, true).B, uint8(200))
+
+		// Halves are written through temporary files that are renamed into place
+		leftovers, err := filepath.Glob(filepath.Join(r.cacheDir, "*.tmp"))
+		require.NoError(t, err)
+		require.Empty(t, leftovers)
+	})
+
+	t.Run("halves are cached per image quality", func(t *testing.T) {
+		require.Equal(t, "brisela_normal_meld_top.jpg", filepath.Base(decklist.Cards[0].Faces[1].ImagePath))
+		require.Equal(t, "brisela_normal_meld_bottom.jpg", filepath.Base(decklist.Cards[1].Faces[1].ImagePath))
+	})
+
+	t.Run("the meld result is printed as a single card", func(t *testing.T) {
+		require.Len(t, decklist.Cards[2].Faces, 1)
+	})
+}
+
+func TestResolveLanguage(t *testing.T) {
+	cards := map[string]scryfall.Card{
+		"bolt": {ID: "bolt", Name: "Lightning Bolt", Lang: "en", Set: "sta", CollectorNumber: "42",
+			ImageURIs: scryfall.ImageURIs{Normal: "https://img/bolt.jpg"}},
+		"ring": {ID: "ring", Name: "Sol Ring", Lang: "en", Set: "c21", CollectorNumber: "263",
+			ImageURIs: scryfall.ImageURIs{Normal: "https://img/ring.jpg"}},
+	}
+	translations := map[string]scryfall.Card{
+		"sta/42/ja": {ID: "bolt-ja", Name: "Lightning Bolt", Lang: "ja", Set: "sta", CollectorNumber: "42",
+			ImageURIs: scryfall.ImageURIs{Normal: "https://img/bolt-ja.jpg"}},
+		"c21/263/de": {ID: "ring-de", Name: "Sol Ring", Lang: "de", Set: "c21", CollectorNumber: "263",
+			ImageURIs: scryfall.ImageURIs{Normal: "https://img/ring-de.jpg"}},
+	}
+
+	var downloads atomic.Int32
+	r := testResolver(t, cards, &downloads)
+	var (
+		mu      sync.Mutex
+		lookups []string
+	)
+	r.findCardLang = func(set, collectorNumber, lang string) (scryfall.Card, error) {
+		key := set + "/" + collectorNumber + "/" + lang
+		mu.Lock()
+		lookups = append(lookups, key)
+		mu.Unlock()
+		if key == "c21/263/it" {
+			return scryfall.Card{}, fmt.Errorf("connection reset: %w", scryfall.ErrNetwork)
+		}
+		card, ok := translations[key]
+		if !ok {
+			return scryfall.Card{}, fmt.Errorf("Error: 404 Not Found: %w", scryfall.ErrNotFound)
+		}
+		return card, nil
+	}
+	r.SetLanguages([]string{"de", "fr"})
+
+	decklist := &model.Decklist{Cards: []model.CardEntry{
+		{Qty: 1, ID: "bolt", Lang: "ja"},
+		{Qty: 1, ID: "ring"},
+		{Qty: 1, ID: "bolt", Lang: "ko"},
+		{Qty: 1, ID: "ring", Lang: "it"},
+	}}
+	_, err := r.Resolve(decklist, nil)
+	require.NoError(t, err)
+
+	t.Run("uses the line's language", func(t *testing.T) {
+		require.Equal(t, "bolt-ja", decklist.Cards[0].Card.ID)
+		require.Contains(t, decklist.Cards[0].Faces[0].ImagePath, "bolt-ja")
+	})
+
+	t.Run("defaults to the preferred languages", func(t *testing.T) {
+		require.Equal(t, "de", decklist.Cards[1].Lang)
+		require.Equal(t, "ring-de", decklist.Cards[1].Card.ID)
+	})
+
+	t.Run("falls back through the preferences to English", func(t *testing.T) {
+		require.Equal(t, "ko", decklist.Cards[2].Lang)
+		require.Equal(t, "en", decklist.Cards[2].Card.Lang)
+		require.Contains(t, lookups, "sta/42/ko")
+		require.Contains(t, lookups, "sta/42/fr")
+		require.NotContains(t, lookups, "sta/42/en")
+	})
+
+	t.Run("other lookup errors fail the entry instead of falling back", func(t *testing.T) {
+		entry := decklist.Cards[3]
+		require.ErrorIs(t, entry.Err, scryfall.ErrNetwork)
+		require.ErrorContains(t, entry.Err, "failed to fetch it printing")
+		require.False(t, entry.Resolved())
+		// Only the Sol Ring line defaulting to German looks up the German printing
+		require.Len(t, slices.DeleteFunc(slices.Clone(lookups), func(key string) bool { return key != "c21/263/de" }), 1,
+			"no fallback after a network error")
+	})
+}
@@ -0,0 +1,148 @@
+// Package stats computes deck statistics from resolved card data: mana
+// curve, colored pips, card types and produced mana.
+package stats
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+)
+
+// MaxCurve is the last mana curve bucket; it counts every card at MaxCurve or more
+const MaxCurve = 7
+
+// Colors in WUBRG order, then colorless
+var Colors = []string{"W", "U", "B", "R", "G", "C"}
+
+// Types are the card types counted in the type breakdown
+var Types = []string{"Creature", "Instant", "Sorcery", "Artifact", "Enchantment", "Planeswalker", "Battle", "Land"}
+
+// singleCardLayouts are layouts whose faces share one card, so every face's
+// mana cost is printed on it: split (including aftermath), flip and adventure
+var singleCardLayouts = map[string]bool{
+	"split":     true,
+	"flip":      true,
+	"adventure": true,
+}
+
+// manaSymbolRegex matches a single mana symbol such as {2}, {W/U} or {G/P}
+var manaSymbolRegex = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Stats summarizes the main deck. Every count is in card copies.
+type Stats struct {
+	Deck       string         `json:"deck"`
+	Cards      int            `json:"cards"`
+	Lands      int            `json:"lands"`
+	AverageCMC float64        `json:"average_cmc"` // Mana value of nonland cards
+	Curve      []int          `json:"curve"`       // Nonland cards at each mana value; the last bucket is MaxCurve+
+	Pips       map[string]int `json:"pips"`        // Colored mana symbols in mana costs; colorless {C} is not counted
+	Hybrid     int            `json:"hybrid_pips"`
+	Phyrexian  int            `json:"phyrexian_pips"`
+	Types      map[string]int `json:"types"`
+	Produced   map[string]int `json:"produced_mana"` // Cards that can produce each color
+	Unresolved int            `json:"unresolved"`    // Copies without card data, left out of every count
+}
+
+// Compute gathers statistics for the main deck of a resolved decklist.
+// Sideboards and maybeboards are left out.
+func Compute(decklist *model.Decklist) *Stats {
+	stats := &Stats{
+		Deck:     decklist.Name,
+		Curve:    make([]int, MaxCurve+1),
+		Pips:     map[string]int{},
+		Types:    map[string]int{},
+		Produced: map[string]int{},
+	}
+
+	totalCMC := 0.0
+	for _, entry := range decklist.Cards {
+		if entry.Board() != model.BoardMain {
+			continue
+		}
+		if !entry.Resolved() {
+			stats.Unresolved += entry.Qty
+			continue
+		}
+		card := entry.Card
+		qty := entry.Qty
+		stats.Cards += qty
+
+		typeLine := frontFace(card).TypeLine
+		for _, cardType := range Types {
+			if strings.Contains(typeLine, cardType) {
+				stats.Types[cardType] += qty
+			}
+		}
+		for _, color := range card.ProducedMana {
+			stats.Produced[color] += qty
+		}
+
+		if strings.Contains(typeLine, "Land") {
+			stats.Lands += qty
+			continue
+		}
+		cmc := int(card.CMC)
+		stats.Curve[min(cmc, MaxCurve)] += qty
+		totalCMC += card.CMC * float64(qty)
+		stats.countPips(manaCost(card), qty)
+	}
+
+	if nonlands := stats.Cards - stats.Lands; nonlands > 0 {
+		stats.AverageCMC = totalCMC / float64(nonlands)
+	}
+	return stats
+}
+
+// countPips adds the colored symbols of a mana cost. Hybrid symbols count
+// towards each of their colors and Phyrexian symbols towards their color;
+// colorless {C} symbols are not colored pips.
+func (s *Stats) countPips(manaCost string, qty int) {
+	for _, match := range manaSymbolRegex.FindAllStringSubmatch(manaCost, -1) {
+		parts := strings.Split(match[1], "/")
+		colors := 0
+		phyrexian := false
+		for _, part := range parts {
+			switch part {
+			case "W", "U", "B", "R", "G":
+				s.Pips[part] += qty
+				colors++
+			case "P":
+				phyrexian = true
+			}
+		}
+		if phyrexian {
+			s.Phyrexian += qty
+		} else if len(parts) > 1 && colors > 0 {
+			s.Hybrid += qty
+		}
+	}
+}
+
+// manaCost returns the mana symbols printed on a card: every face's cost for
+// single-card layouts, otherwise the front face's
+func manaCost(card scryfall.Card) string {
+	if !singleCardLayouts[card.Layout] || len(card.CardFaces) == 0 {
+		return frontFace(card).ManaCost
+	}
+	costs := make([]string, 0, len(card.CardFaces))
+	for _, face := range card.CardFaces {
+		costs = append(costs, face.ManaCost)
+	}
+	return strings.Join(costs, "")
+}
+
+// frontFace returns the type line and mana cost printed on the front of the card
+func frontFace(card scryfall.Card) scryfall.CardFace {
+	face := scryfall.CardFace{TypeLine: card.TypeLine, ManaCost: card.ManaCost}
+	if len(card.CardFaces) > 0 {
+		if card.CardFaces[0].TypeLine != "" {
+			face.TypeLine = card.CardFaces[0].TypeLine
+		}
+		if card.CardFaces[0].ManaCost != "" {
+			face.ManaCost = card.CardFaces[0].ManaCost
+		}
+	}
+	return face
+}
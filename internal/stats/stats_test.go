@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+// entry builds a resolved decklist entry
+func entry(qty int, card scryfall.Card, section string) model.CardEntry {
+	return model.CardEntry{Qty: qty, ID: card.Name, Section: section, Card: card, Faces: []model.Face{{Name: card.Name}}}
+}
+
+func TestCompute(t *testing.T) {
+	decklist := &model.Decklist{Name: "Test", Cards: []model.CardEntry{
+		entry(4, scryfall.Card{Name: "Lightning Bolt", TypeLine: "Instant", ManaCost: "{R}", CMC: 1}, ""),
+		entry(2, scryfall.Card{Name: "Boros Charm", TypeLine: "Instant", ManaCost: "{R}{W}", CMC: 2}, ""),
+		entry(1, scryfall.Card{Name: "Figure of Destiny", TypeLine: "Creature — Kithkin Spirit", ManaCost: "{R/W}", CMC: 1}, ""),
+		entry(1, scryfall.Card{Name: "Dismember", TypeLine: "Instant", ManaCost: "{1}{B/P}{B/P}", CMC: 3}, ""),
+		entry(1, scryfall.Card{Name: "Emrakul, the Aeons Torn", TypeLine: "Legendary Creature — Eldrazi", ManaCost: "{15}", CMC: 15}, ""),
+		entry(1, scryfall.Card{Name: "Delver of Secrets // Insectile Aberration", CMC: 1, CardFaces: []scryfall.CardFace{
+			{TypeLine: "Creature — Human Wizard", ManaCost: "{U}"},
+			{TypeLine: "Creature — Human Insect"},
+		}}, ""),
+		entry(8, scryfall.Card{Name: "Mountain", TypeLine: "Basic Land — Mountain", ProducedMana: []string{"R"}}, ""),
+		entry(2, scryfall.Card{Name: "Sacred Foundry", TypeLine: "Land — Mountain Plains", ProducedMana: []string{"R", "W"}}, ""),
+		entry(1, scryfall.Card{Name: "Sol Ring", TypeLine: "Artifact", ManaCost: "{1}", CMC: 1, ProducedMana: []string{"C"}}, ""),
+		entry(3, scryfall.Card{Name: "Smash to Smithereens", TypeLine: "Instant", ManaCost: "{1}{R}", CMC: 2}, "Sideboard"),
+		{Qty: 2, ID: "missing"},
+	}}
+
+	stats := Compute(decklist)
+
+	t.Run("counts", func(t *testing.T) {
+		require.Equal(t, 21, stats.Cards)
+		require.Equal(t, 10, stats.Lands)
+		require.Equal(t, 2, stats.Unresolved)
+		require.InDelta(t, (4+4+1+3+15+1+1)/11.0, stats.AverageCMC, 0.001)
+		require.Equal(t, []int{0, 7, 2, 1, 0, 0, 0, 1}, stats.Curve)
+	})
+
+	t.Run("pips", func(t *testing.T) {
+		require.Equal(t, map[string]int{"R": 7, "W": 3, "B": 2, "U": 1}, stats.Pips)
+		require.Equal(t, 1, stats.Hybrid)
+		require.Equal(t, 2, stats.Phyrexian)
+	})
+
+	t.Run("types and produced mana", func(t *testing.T) {
+		require.Equal(t, map[string]int{"Instant": 7, "Creature": 3, "Land": 10, "Artifact": 1}, stats.Types)
+		require.Equal(t, map[string]int{"R": 10, "W": 2, "C": 1}, stats.Produced)
+	})
+
+	t.Run("text tables", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, stats.WriteText(&out))
+		text := out.String()
+		require.Contains(t, text, "Test: 21 card(s), 10 land(s), average mana value 2.64\n")
+		require.Contains(t, text, "  7+              1 ##\n")
+		require.Contains(t, text, "  1 hybrid, 2 Phyrexian\n")
+		require.Contains(t, text, "  Land           10 ###############\n")
+	})
+}
+
+func TestPips(t *testing.T) {
+	t.Run("every half of a single card counts", func(t *testing.T) {
+		stats := Compute(&model.Decklist{Cards: []model.CardEntry{
+			entry(1, scryfall.Card{Name: "Fire // Ice", Layout: "split", CMC: 4, CardFaces: []scryfall.CardFace{
+				{TypeLine: "Instant", ManaCost: "{1}{R}"},
+				{TypeLine: "Instant", ManaCost: "{1}{U}"},
+			}}, ""),
+			entry(2, scryfall.Card{Name: "Bonecrusher Giant // Stomp", Layout: "adventure", CMC: 3, CardFaces: []scryfall.CardFace{
+				{TypeLine: "Creature — Giant", ManaCost: "{2}{R}"},
+				{TypeLine: "Instant — Adventure", ManaCost: "{1}{R}"},
+			}}, ""),
+			entry(1, scryfall.Card{Name: "Brazen Borrower // Petty Theft", Layout: "adventure", CMC: 3, CardFaces: []scryfall.CardFace{
+				{TypeLine: "Creature — Faerie Rogue", ManaCost: "{1}{U}{U}"},
+				{TypeLine: "Instant — Adventure", ManaCost: "{1}{U}"},
+			}}, ""),
+		}})
+		require.Equal(t, map[string]int{"R": 5, "U": 4}, stats.Pips)
+	})
+
+	t.Run("only the front of a double-faced card counts", func(t *testing.T) {
+		stats := Compute(&model.Decklist{Cards: []model.CardEntry{
+			entry(1, scryfall.Card{Name: "Valki, God of Lies // Tibalt, Cosmic Impostor", Layout: "modal_dfc", CMC: 2,
+				CardFaces: []scryfall.CardFace{
+					{TypeLine: "Legendary Creature — God", ManaCost: "{1}{B}"},
+					{TypeLine: "Legendary Planeswalker — Tibalt", ManaCost: "{5}{B}{R}"},
+				}}, ""),
+		}})
+		require.Equal(t, map[string]int{"B": 1}, stats.Pips)
+	})
+
+	t.Run("colorless symbols are not colored pips", func(t *testing.T) {
+		stats := Compute(&model.Decklist{Cards: []model.CardEntry{
+			entry(1, scryfall.Card{Name: "Thought-Knot Seer", TypeLine: "Creature — Eldrazi", ManaCost: "{3}{C}", CMC: 4}, ""),
+		}})
+		require.Empty(t, stats.Pips)
+	})
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxBarWidth is the widest bar drawn in the text tables
+const maxBarWidth = 30
+
+// Lines formats the statistics as plain text tables, one line per entry
+func (s *Stats) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("%s: %d card(s), %d land(s), average mana value %.2f", s.Deck, s.Cards, s.Lands, s.AverageCMC),
+	}
+	if s.Unresolved > 0 {
+		lines = append(lines, fmt.Sprintf("%d card(s) could not be resolved and are not counted", s.Unresolved))
+	}
+
+	lines = append(lines, "", "Mana curve")
+	for cmc, count := range s.Curve {
+		label := fmt.Sprint(cmc)
+		if cmc == MaxCurve {
+			label += "+"
+		}
+		lines = append(lines, barLine(label, count, s.Cards))
+	}
+
+	lines = append(lines, "", "Colored pips")
+	for _, color := range Colors {
+		if s.Pips[color] > 0 {
+			lines = append(lines, barLine(color, s.Pips[color], maxValue(s.Pips)))
+		}
+	}
+	if s.Hybrid > 0 || s.Phyrexian > 0 {
+		lines = append(lines, fmt.Sprintf("  %d hybrid, %d Phyrexian", s.Hybrid, s.Phyrexian))
+	}
+
+	lines = append(lines, "", "Card types")
+	for _, cardType := range Types {
+		if s.Types[cardType] > 0 {
+			lines = append(lines, barLine(cardType, s.Types[cardType], s.Cards))
+		}
+	}
+
+	lines = append(lines, "", "Produced mana")
+	for _, color := range Colors {
+		if s.Produced[color] > 0 {
+			lines = append(lines, barLine(color, s.Produced[color], s.Cards))
+		}
+	}
+	return lines
+}
+
+// WriteText writes the statistics as text tables
+func (s *Stats) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, strings.Join(s.Lines(), "\n")+"\n")
+	return err
+}
+
+// barLine formats a labelled count with a bar scaled against total
+func barLine(label string, count, total int) string {
+	width := 0
+	if total > 0 {
+		width = (count*maxBarWidth + total - 1) / total
+	}
+	return fmt.Sprintf("  %-12s %4d %s", label, count, strings.Repeat("#", width))
+}
+
+// maxValue returns the largest value in a map
+func maxValue(values map[string]int) int {
+	largest := 0
+	for _, value := range values {
+		largest = max(largest, value)
+	}
+	return largest
+}
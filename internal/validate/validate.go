@@ -0,0 +1,147 @@
+// Package validate checks a parsed and resolved decklist for problems that
+// would spoil a print run, without rendering anything.
+package validate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/daltonalley/deckforge-cli/internal/deck"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+)
+
+// Issue kinds
+const (
+	KindInvalidRow   = "invalid_row"       // Row could not be parsed
+	KindUnknownID    = "unknown_id"        // Scryfall has no card with the ID
+	KindFetchFailed  = "fetch_failed"      // Card data could not be fetched
+	KindMissingImage = "missing_image"     // Image could not be downloaded
+	KindPlaceholder  = "placeholder_image" // Scryfall only has a placeholder or low resolution scan
+	KindDigital      = "digital_only"      // Printing only exists in digital games
+)
+
+// Issue is a single problem found in the decklist
+type Issue struct {
+	Line    int    `json:"line"`
+	Card    string `json:"card,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Result is the outcome of validating a decklist
+type Result struct {
+	Deck     string  `json:"deck"`
+	Entries  int     `json:"entries"`  // Valid decklist lines
+	Resolved int     `json:"resolved"` // Lines with card data
+	Copies   int     `json:"copies"`   // Total card copies on valid lines
+	Pages    int     `json:"pages"`    // Pages needed for the resolved cards
+	Errors   []Issue `json:"errors"`
+	Warnings []Issue `json:"warnings"`
+}
+
+// Check collects the problems in a resolved decklist and the rows that were
+// skipped while parsing it
+func Check(decklist *model.Decklist, rowErrors []deck.RowError) *Result {
+	result := &Result{
+		Deck:     decklist.Name,
+		Entries:  len(decklist.Cards),
+		Errors:   []Issue{},
+		Warnings: []Issue{},
+	}
+	for _, rowErr := range rowErrors {
+		result.Errors = append(result.Errors, Issue{Line: rowErr.Line, Kind: KindInvalidRow, Message: rowErr.Error()})
+	}
+
+	resolved := &model.Decklist{}
+	for _, entry := range decklist.Cards {
+		result.Copies += entry.Qty
+		if entry.Resolved() {
+			resolved.Cards = append(resolved.Cards, entry)
+		}
+		result.checkEntry(entry)
+	}
+	result.Resolved = len(resolved.Cards)
+	result.Pages = deck.CalculateTotalPages(resolved)
+	return result
+}
+
+// checkEntry records the errors and warnings for one decklist entry
+func (r *Result) checkEntry(entry model.CardEntry) {
+	issue := func(kind, message string) Issue {
+		return Issue{Line: entry.Line, Card: entry.DisplayName(), ID: entry.ID, Kind: kind, Message: message}
+	}
+
+	if !entry.Resolved() {
+		kind := KindFetchFailed
+		if failure.KindOf(entry.Err) == failure.NotFound {
+			kind = KindUnknownID
+		}
+		message := "card was not resolved"
+		if entry.Err != nil {
+			message = entry.Err.Error()
+		}
+		r.Errors = append(r.Errors, issue(kind, message))
+		return
+	}
+
+	for _, face := range entry.Faces {
+		if face.Err != nil {
+			r.Errors = append(r.Errors, issue(KindMissingImage, fmt.Sprintf("%s: %v", face.Name, face.Err)))
+		}
+	}
+	switch entry.Card.ImageStatus {
+	case "placeholder", "missing":
+		r.Warnings = append(r.Warnings, issue(KindPlaceholder, fmt.Sprintf("Scryfall image is a %s", entry.Card.ImageStatus)))
+	case "lowres":
+		r.Warnings = append(r.Warnings, issue(KindPlaceholder, "Scryfall image is low resolution"))
+	}
+	if entry.Card.Digital {
+		r.Warnings = append(r.Warnings, issue(KindDigital, "printing is digital-only"))
+	}
+}
+
+// Valid reports whether the decklist can be generated without errors
+func (r *Result) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// WriteText writes a human-readable summary of the result
+func (r *Result) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("%s: %d line(s), %d card(s), %d resolved, %d page(s)\n", r.Deck, r.Entries, r.Copies, r.Resolved, r.Pages)
+	for _, section := range []struct {
+		title  string
+		issues []Issue
+	}{{"Errors", r.Errors}, {"Warnings", r.Warnings}} {
+		if len(section.issues) == 0 {
+			continue
+		}
+		printf("\n%s:\n", section.title)
+		for _, issue := range section.issues {
+			printf("   • %s\n", issue)
+		}
+	}
+
+	if r.Valid() {
+		printf("\n✅ Decklist is valid with %d warning(s)\n", len(r.Warnings))
+	} else {
+		printf("\n❌ Decklist has %d error(s) and %d warning(s)\n", len(r.Errors), len(r.Warnings))
+	}
+	return err
+}
+
+// String formats the issue as a single line
+func (i Issue) String() string {
+	if i.Card == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("Line %d, %s: %s", i.Line, i.Card, i.Message)
+}
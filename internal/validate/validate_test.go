@@ -0,0 +1,68 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/daltonalley/deckforge-cli/internal/deck"
+	"github.com/daltonalley/deckforge-cli/internal/failure"
+	"github.com/daltonalley/deckforge-cli/internal/model"
+	"github.com/daltonalley/deckforge-cli/scryfall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	decklist := &model.Decklist{Name: "Delver", Cards: []model.CardEntry{
+		{Line: 1, Qty: 4, ID: "bolt", Card: scryfall.Card{Name: "Lightning Bolt"}, Faces: []model.Face{{Name: "Lightning Bolt"}}},
+		{Line: 3, Qty: 2, ID: "delver", Card: scryfall.Card{Name: "Delver of Secrets", CardFaces: []scryfall.CardFace{{}, {}}},
+			Faces: []model.Face{{Name: "Delver of Secrets"}, {Name: "Insectile Aberration"}}},
+		{Line: 4, Qty: 1, ID: "missing", Name: "Missing Card",
+			Err: failure.New(failure.NotFound, errors.New("Error: 404 Not Found"))},
+		{Line: 5, Qty: 1, ID: "offline", Name: "Offline Card", Err: errors.New("connection refused")},
+		{Line: 6, Qty: 1, ID: "blank", Card: scryfall.Card{Name: "Blank Card", ImageStatus: "placeholder"},
+			Faces: []model.Face{{Name: "Blank Card", Err: errors.New("no image")}}},
+		{Line: 7, Qty: 1, ID: "arena", Card: scryfall.Card{Name: "Arena Card", Digital: true, ImageStatus: "lowres"},
+			Faces: []model.Face{{Name: "Arena Card"}}},
+	}}
+	rowErrors := []deck.RowError{{Line: 2, Err: fmt.Errorf("quantity must be positive at line 2, got 0")}}
+
+	result := Check(decklist, rowErrors)
+
+	t.Run("counts resolved pages", func(t *testing.T) {
+		require.Equal(t, 6, result.Entries)
+		require.Equal(t, 4, result.Resolved)
+		require.Equal(t, 10, result.Copies)
+		require.Equal(t, 10, result.Pages) // 4 + 2x2 + 1 + 1
+		require.False(t, result.Valid())
+	})
+
+	t.Run("classifies issues", func(t *testing.T) {
+		var errorKinds, warningKinds []string
+		for _, issue := range result.Errors {
+			errorKinds = append(errorKinds, issue.Kind)
+		}
+		for _, issue := range result.Warnings {
+			warningKinds = append(warningKinds, issue.Kind)
+		}
+		require.Equal(t, []string{KindInvalidRow, KindUnknownID, KindFetchFailed, KindMissingImage}, errorKinds)
+		require.Equal(t, []string{KindPlaceholder, KindPlaceholder, KindDigital}, warningKinds)
+		require.Equal(t, "Line 4, Missing Card: Error: 404 Not Found", result.Errors[1].String())
+	})
+
+	t.Run("text output", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, result.WriteText(&out))
+		require.Contains(t, out.String(), "Delver: 6 line(s), 10 card(s), 4 resolved, 10 page(s)")
+		require.Contains(t, out.String(), "   • quantity must be positive at line 2, got 0\n")
+		require.Contains(t, out.String(), "   • Line 7, Arena Card: printing is digital-only\n")
+		require.Contains(t, out.String(), "❌ Decklist has 4 error(s) and 3 warning(s)")
+	})
+
+	t.Run("valid decklist", func(t *testing.T) {
+		result := Check(&model.Decklist{Cards: decklist.Cards[:2]}, nil)
+		require.True(t, result.Valid())
+		require.Empty(t, result.Warnings)
+	})
+}
@@ -3,9 +3,10 @@ package scryfall
 import (
 	"fmt"
 	"io"
-	"net/http"
-	"os"
+	"net/url"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/bytedance/sonic"
 )
@@ -80,6 +81,35 @@ type Card struct {
 	PurchaseURIs    PurchaseURIs  `json:"purchase_uris"`
 }
 
+// faceImageLayouts are layouts printed as a separate card image per face
+var faceImageLayouts = map[string]bool{
+	"transform":          true,
+	"modal_dfc":          true,
+	"battle":             true,
+	"double_faced_token": true,
+	"reversible_card":    true,
+	"art_series":         true,
+}
+
+// HasFaceImages reports whether each face of the card is printed as its own
+// card image. Split, flip, adventure and similar layouts list card_faces but
+// print them all on one image. Unknown layouts have face images only when
+// every face has its own image.
+func (c Card) HasFaceImages() bool {
+	if len(c.CardFaces) == 0 {
+		return false
+	}
+	if faceImageLayouts[c.Layout] {
+		return true
+	}
+	for _, face := range c.CardFaces {
+		if face.ImageURIs.Normal == "" {
+			return false
+		}
+	}
+	return true
+}
+
 type ImageURIs struct {
 	Small      string `json:"small"`
 	Normal     string `json:"normal"`
@@ -89,6 +119,33 @@ type ImageURIs struct {
 	BorderCrop string `json:"border_crop"`
 }
 
+// Card image qualities, from smallest to largest
+const (
+	QualityNormal = "normal"
+	QualityLarge  = "large"
+	QualityPNG    = "png" // Lossless, with transparent rounded corners
+)
+
+// ImageWidths are the pixel widths of Scryfall's card images by quality
+var ImageWidths = map[string]int{
+	QualityNormal: 488,
+	QualityLarge:  672,
+	QualityPNG:    745,
+}
+
+// URL returns the image URL for a quality, using the normal image for
+// unknown qualities
+func (u ImageURIs) URL(quality string) string {
+	switch quality {
+	case QualityLarge:
+		return u.Large
+	case QualityPNG:
+		return u.PNG
+	default:
+		return u.Normal
+	}
+}
+
 type RelatedCard struct {
 	ID        string `json:"id"`
 	Component string `json:"component"`
@@ -137,6 +194,65 @@ type Legalities struct {
 	Predh           string `json:"predh"`
 }
 
+// Legality statuses used in Legalities
+const (
+	Legal      = "legal"
+	NotLegal   = "not_legal"
+	Restricted = "restricted"
+	Banned     = "banned"
+)
+
+// Status returns the legality in a format, named as in Scryfall's legalities
+// object, and whether the format is known
+func (l Legalities) Status(format string) (string, bool) {
+	switch format {
+	case "standard":
+		return l.Standard, true
+	case "future":
+		return l.Future, true
+	case "historic":
+		return l.Historic, true
+	case "timeless":
+		return l.Timeless, true
+	case "gladiator":
+		return l.Gladiator, true
+	case "pioneer":
+		return l.Pioneer, true
+	case "modern":
+		return l.Modern, true
+	case "legacy":
+		return l.Legacy, true
+	case "pauper":
+		return l.Pauper, true
+	case "vintage":
+		return l.Vintage, true
+	case "penny":
+		return l.Penny, true
+	case "commander":
+		return l.Commander, true
+	case "oathbreaker":
+		return l.Oathbreaker, true
+	case "standardbrawl":
+		return l.Standardbrawl, true
+	case "brawl":
+		return l.Brawl, true
+	case "alchemy":
+		return l.Alchemy, true
+	case "paupercommander":
+		return l.Paupercommander, true
+	case "duel":
+		return l.Duel, true
+	case "oldschool":
+		return l.Oldschool, true
+	case "premodern":
+		return l.Premodern, true
+	case "predh":
+		return l.Predh, true
+	default:
+		return "", false
+	}
+}
+
 type Prices struct {
 	USD       string `json:"usd"`
 	USDFoil   string `json:"usd_foil"`
@@ -162,37 +278,47 @@ type PurchaseURIs struct {
 const baseURL = "https://api.scryfall.com/cards/"
 
 func FindCardByID(id string) (Card, error) {
-	req, err := http.NewRequest("GET", baseURL+id, nil)
-	if err != nil {
-		return Card{}, err
-	}
+	return FindCardByURI(baseURL + id)
+}
 
-	req.Header.Set("User-Agent", "go-scryfall/0.1.2")
-	req.Header.Set("Accept", "application/json")
+// FindCardInLanguage fetches the printing with the given set code and
+// collector number in another language
+func FindCardInLanguage(set, collectorNumber, lang string) (Card, error) {
+	return FindCardByURI(baseURL + url.PathEscape(set) + "/" + url.PathEscape(collectorNumber) + "/" + url.PathEscape(lang))
+}
 
-	c := http.Client{}
-	resp, err := c.Do(req)
-	if err != nil {
-		return Card{}, err
-	}
-	defer resp.Body.Close()
+// Languages are the language codes Scryfall uses for printings
+var Languages = []string{"en", "es", "fr", "de", "it", "pt", "ja", "ko", "ru", "zhs", "zht", "he", "la", "grc", "ar", "sa", "ph", "qya"}
+
+// IsLanguage reports whether code is a Scryfall language code
+func IsLanguage(code string) bool {
+	return slices.Contains(Languages, code)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return Card{}, fmt.Errorf("Error: %s", resp.Status)
+// FindCardByURI fetches a single card from a Scryfall API URI, such as the
+// URI of a related card
+func FindCardByURI(uri string) (Card, error) {
+	var card Card
+	if err := getJSON(uri, &card); err != nil {
+		return Card{}, err
 	}
+	return card, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getJSON requests a Scryfall API URI and decodes the JSON response into v
+func getJSON(uri string, v any) error {
+	resp, err := get(uri, "application/json")
 	if err != nil {
-		return Card{}, err
+		return err
 	}
+	defer resp.Body.Close()
 
-	var card Card
-	err = sonic.Unmarshal(body, &card)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Card{}, err
+		return networkError(err)
 	}
 
-	return card, nil
+	return sonic.Unmarshal(body, v)
 }
 
 // DownloadCardImage downloads a card image from Scryfall and caches it locally
@@ -205,70 +331,26 @@ func DownloadCardImage(cardID, cacheDir, quality string) (string, error) {
 		return "", fmt.Errorf("failed to find card %s: %w", cardID, err)
 	}
 
-	// Determine image URL based on quality
-	// For double-sided cards, use face ImageURIs if available
-	var imageURL string
-	switch quality {
-	case "normal":
-		imageURL = card.ImageURIs.Normal
-	case "large":
-		imageURL = card.ImageURIs.Large
-	case "png":
-		imageURL = card.ImageURIs.PNG
-	default:
-		imageURL = card.ImageURIs.Normal
-	}
-
+	imageURL := card.ImageURIs.URL(quality)
 	if imageURL == "" {
 		return "", fmt.Errorf("no image URL available for card %s", cardID)
 	}
 
-	// Create cache filename
-	cacheFile := filepath.Join(cacheDir, fmt.Sprintf("%s_%s.jpg", cardID, quality))
-
-	// Check if cached file exists
-	if _, err := os.Stat(cacheFile); err == nil {
-		return cacheFile, nil
-	}
-
-	// Download the image
-	req, err := http.NewRequest("GET", imageURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request for %s: %w", imageURL, err)
-	}
-
-	req.Header.Set("User-Agent", "go-scryfall/0.1.2")
-	req.Header.Set("Accept", "image/*")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	cacheFile := CachedImagePath(fmt.Sprintf("%s_%s", cardID, quality), cacheDir)
+	path, err := downloadToCache(imageURL, cacheFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to download image for %s: %w", cardID, err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download image for %s: HTTP %d", cardID, resp.StatusCode)
-	}
-
-	// Create cache directory if it doesn't exist
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
-	// Save image to cache file
-	out, err := os.Create(cacheFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cache file: %w", err)
-	}
-	defer out.Close()
+	return path, nil
+}
 
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to save image: %w", err)
+// CachedImagePath returns where the image for a cache key is stored. Keys
+// ending in the png quality are PNG scans; every other image is a JPEG.
+func CachedImagePath(cacheKey, cacheDir string) string {
+	if strings.HasSuffix(cacheKey, "_"+QualityPNG) {
+		return filepath.Join(cacheDir, cacheKey+".png")
 	}
-
-	return cacheFile, nil
+	return filepath.Join(cacheDir, fmt.Sprintf("%s.jpg", cacheKey))
 }
 
 // DownloadImageFromURL downloads an image from a direct URL and caches it locally
@@ -277,50 +359,9 @@ func DownloadImageFromURL(imageURL, cacheKey, cacheDir string) (string, error) {
 		return "", fmt.Errorf("empty image URL")
 	}
 
-	// Create cache filename
-	cacheFile := filepath.Join(cacheDir, fmt.Sprintf("%s.jpg", cacheKey))
-
-	// Check if cached file exists
-	if _, err := os.Stat(cacheFile); err == nil {
-		return cacheFile, nil
-	}
-
-	// Download the image
-	req, err := http.NewRequest("GET", imageURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request for %s: %w", imageURL, err)
-	}
-
-	req.Header.Set("User-Agent", "go-scryfall/0.1.2")
-	req.Header.Set("Accept", "image/*")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	path, err := downloadToCache(imageURL, CachedImagePath(cacheKey, cacheDir))
 	if err != nil {
 		return "", fmt.Errorf("failed to download image: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download image: HTTP %d", resp.StatusCode)
-	}
-
-	// Create cache directory if it doesn't exist
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
-	// Save image to cache file
-	out, err := os.Create(cacheFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cache file: %w", err)
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to save image: %w", err)
-	}
-
-	return cacheFile, nil
+	return path, nil
 }
@@ -74,3 +74,46 @@ func TestDownloadCardImage(t *testing.T) {
 		require.Equal(t, info1.ModTime(), info2.ModTime())
 	})
 }
+
+func TestLegalities(t *testing.T) {
+	legalities := Legalities{Modern: Legal, Vintage: Restricted, Commander: Banned}
+
+	status, ok := legalities.Status("vintage")
+	require.True(t, ok)
+	require.Equal(t, Restricted, status)
+
+	status, ok = legalities.Status("commander")
+	require.True(t, ok)
+	require.Equal(t, Banned, status)
+
+	_, ok = legalities.Status("tiny-leaders")
+	require.False(t, ok)
+}
+
+func TestHasFaceImages(t *testing.T) {
+	faces := func(images bool) []CardFace {
+		uris := ImageURIs{}
+		if images {
+			uris.Normal = "https://img/face.jpg"
+		}
+		return []CardFace{{Name: "Front", ImageURIs: uris}, {Name: "Back", ImageURIs: uris}}
+	}
+
+	t.Run("double-faced layouts", func(t *testing.T) {
+		require.True(t, Card{Layout: "transform", CardFaces: faces(true)}.HasFaceImages())
+		require.True(t, Card{Layout: "modal_dfc", CardFaces: faces(true)}.HasFaceImages())
+		require.True(t, Card{Layout: "reversible_card", CardFaces: faces(false)}.HasFaceImages())
+	})
+
+	t.Run("faces sharing one image", func(t *testing.T) {
+		require.False(t, Card{Layout: "split", CardFaces: faces(false)}.HasFaceImages())
+		require.False(t, Card{Layout: "flip", CardFaces: faces(false)}.HasFaceImages())
+		require.False(t, Card{Layout: "adventure", CardFaces: faces(false)}.HasFaceImages())
+		require.False(t, Card{Layout: "normal"}.HasFaceImages())
+	})
+
+	t.Run("unknown layouts use the face images", func(t *testing.T) {
+		require.True(t, Card{Layout: "future", CardFaces: faces(true)}.HasFaceImages())
+		require.False(t, Card{Layout: "future", CardFaces: faces(false)}.HasFaceImages())
+	})
+}
@@ -0,0 +1,194 @@
+package scryfall
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Scryfall asks API clients to stay under about 10 requests per second. Its
+// image CDN (cards.scryfall.io) is not rate limited.
+const (
+	apiHost         = "api.scryfall.com"
+	requestInterval = 100 * time.Millisecond
+	maxRetries      = 3
+	userAgent       = "go-scryfall/0.1.2"
+)
+
+var (
+	httpClient = &http.Client{}
+
+	// apiLimiter spaces API requests from every goroutine
+	apiLimiter = newRateLimiter(requestInterval)
+
+	// retryDelay is the first backoff after a 429 without Retry-After; it
+	// doubles with each retry. Replaced in tests.
+	retryDelay = time.Second
+
+	// imageDownloads shares concurrent downloads of the same cache file
+	imageDownloads flightGroup
+)
+
+// rateLimiter spaces calls to wait at least interval apart
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter creates a limiter allowing one call per interval
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{interval: interval}
+}
+
+// wait blocks until the caller's turn
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// get requests uri, waiting for the rate limiter on API requests and retrying
+// 429 responses with backoff. Any response other than 200 is returned as an
+// *APIError; the caller closes the body of a successful response.
+func get(uri, accept string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", accept)
+
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		if req.URL.Host == apiHost {
+			apiLimiter.wait()
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, networkError(err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt == maxRetries {
+			defer resp.Body.Close()
+			return nil, newAPIError(resp)
+		}
+
+		wait := retryAfter(resp, delay)
+		resp.Body.Close()
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
+
+// retryAfter reads a Retry-After header in seconds, or returns fallback
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// WriteCached writes a cache file through a temporary file in the same
+// directory and renames it into place, so readers never see a partly written
+// file and a failed write leaves nothing behind
+func WriteCached(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// downloadToCache downloads imageURL to cacheFile unless it is already
+// cached. Concurrent downloads of the same file share a single request.
+func downloadToCache(imageURL, cacheFile string) (string, error) {
+	return imageDownloads.do(cacheFile, func() (string, error) {
+		if _, err := os.Stat(cacheFile); err == nil {
+			return cacheFile, nil
+		}
+
+		resp, err := get(imageURL, "image/*")
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+			return "", fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		err = WriteCached(cacheFile, func(w io.Writer) error {
+			if _, err := io.Copy(w, resp.Body); err != nil {
+				return networkError(err)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to save image: %w", err)
+		}
+		return cacheFile, nil
+	})
+}
+
+// flightGroup runs one call per key at a time; callers arriving while a call
+// is in flight wait for it and share its result
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is an in-progress or finished call
+type flight struct {
+	done  chan struct{}
+	value string
+	err   error
+}
+
+// do runs fn for key unless a call for key is already in flight
+func (g *flightGroup) do(key string, fn func() (string, error)) (string, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &flight{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.value, call.err = fn()
+	close(call.done)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return call.value, call.err
+}
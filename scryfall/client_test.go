@@ -0,0 +1,119 @@
+package scryfall
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = time.Second })
+
+	t.Run("retries rate-limited requests", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"object":"card","name":"Lightning Bolt"}`)
+		}))
+		defer server.Close()
+
+		card, err := FindCardByURI(server.URL)
+		require.NoError(t, err)
+		require.Equal(t, "Lightning Bolt", card.Name)
+		require.EqualValues(t, 3, requests.Load())
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		_, err := FindCardByURI(server.URL)
+		require.ErrorIs(t, err, ErrRateLimited)
+		require.EqualValues(t, maxRetries+1, requests.Load())
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+		require.Equal(t, 2*time.Second, retryAfter(resp, time.Millisecond))
+		require.Equal(t, time.Millisecond, retryAfter(&http.Response{}, time.Millisecond))
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20 * time.Millisecond)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.wait()
+		}()
+	}
+	wg.Wait()
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
+
+func TestDownloadToCache(t *testing.T) {
+	t.Run("concurrent downloads of one file share a request", func(t *testing.T) {
+		var requests atomic.Int32
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			fmt.Fprint(w, "jpeg bytes")
+		}))
+		defer server.Close()
+
+		cacheFile := filepath.Join(t.TempDir(), "bolt_normal.jpg")
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				path, err := downloadToCache(server.URL, cacheFile)
+				require.NoError(t, err)
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.Equal(t, "jpeg bytes", string(data))
+			}()
+		}
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		require.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("failed writes leave nothing in the cache", func(t *testing.T) {
+		dir := t.TempDir()
+		cacheFile := filepath.Join(dir, "bolt_normal.jpg")
+		err := WriteCached(cacheFile, func(w io.Writer) error {
+			if _, err := w.Write([]byte("partial")); err != nil {
+				return err
+			}
+			return errors.New("connection reset")
+		})
+		require.EqualError(t, err, "connection reset")
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
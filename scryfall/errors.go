@@ -0,0 +1,67 @@
+package scryfall
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+)
+
+// Sentinel errors for classifying failures with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrInvalidInput = errors.New("invalid request")
+	ErrNetwork      = errors.New("network error")
+)
+
+// APIError is Scryfall's JSON error object, also used for non-JSON HTTP
+// failures such as image downloads
+type APIError struct {
+	Object   string   `json:"object"`
+	Status   int      `json:"status"`
+	Code     string   `json:"code"`
+	Details  string   `json:"details"`
+	Type     string   `json:"type"`
+	Warnings []string `json:"warnings"`
+}
+
+// Error describes the failure with Scryfall's details when available
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("Error: %d %s: %s", e.Status, http.StatusText(e.Status), e.Details)
+	}
+	return fmt.Sprintf("Error: %d %s", e.Status, http.StatusText(e.Status))
+}
+
+// Is matches the sentinel error for the HTTP status
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	case ErrInvalidInput:
+		return e.Status == http.StatusBadRequest || e.Status == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response, using Scryfall's
+// JSON error object when the body contains one
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil || sonic.Unmarshal(body, apiErr) != nil || apiErr.Object != "error" {
+		apiErr = &APIError{}
+	}
+	apiErr.Status = resp.StatusCode
+	return apiErr
+}
+
+// networkError marks a failed request as a network failure
+func networkError(err error) error {
+	return fmt.Errorf("%w: %w", ErrNetwork, err)
+}
@@ -0,0 +1,47 @@
+package scryfall
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// response builds a failed HTTP response with the given body
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestAPIError(t *testing.T) {
+	t.Run("parses Scryfall's error object", func(t *testing.T) {
+		err := newAPIError(response(http.StatusNotFound,
+			`{"object":"error","code":"not_found","status":404,"details":"No card found with the given ID or set code and collector number."}`))
+		require.Equal(t, "not_found", err.Code)
+		require.Equal(t, "Error: 404 Not Found: No card found with the given ID or set code and collector number.", err.Error())
+		require.ErrorIs(t, err, ErrNotFound)
+		require.NotErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("falls back to the HTTP status for other bodies", func(t *testing.T) {
+		err := newAPIError(response(http.StatusTooManyRequests, "<html>slow down</html>"))
+		require.Equal(t, "Error: 429 Too Many Requests", err.Error())
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		err := newAPIError(response(http.StatusBadRequest, `{"object":"error","code":"bad_request","status":400,"details":"Invalid ID"}`))
+		require.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("wrapped errors keep their kind", func(t *testing.T) {
+		err := errors.Join(errors.New("context"), newAPIError(response(http.StatusNotFound, "")))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.ErrorIs(t, networkError(errors.New("connection reset")), ErrNetwork)
+	})
+}
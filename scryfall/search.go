@@ -0,0 +1,28 @@
+package scryfall
+
+// List is a page of cards from a Scryfall search
+type List struct {
+	Object   string `json:"object"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+	Data     []Card `json:"data"`
+}
+
+// SearchCards fetches every card from a Scryfall search URI, following
+// pagination, e.g. a card's PrintsSearchURI for all of its printings
+func SearchCards(uri string) ([]Card, error) {
+	var cards []Card
+	for uri != "" {
+		var page List
+		if err := getJSON(uri, &page); err != nil {
+			return nil, err
+		}
+		cards = append(cards, page.Data...)
+
+		uri = ""
+		if page.HasMore {
+			uri = page.NextPage
+		}
+	}
+	return cards, nil
+}
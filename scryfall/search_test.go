@@ -0,0 +1,44 @@
+package scryfall
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCards(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"object":"list","has_more":true,"next_page":"%s/search?page=2","data":[{"name":"Lightning Bolt","set":"lea"}]}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{"object":"list","has_more":false,"data":[{"name":"Lightning Bolt","set":"m10"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("follows pagination", func(t *testing.T) {
+		cards, err := SearchCards(server.URL + "/search")
+		require.NoError(t, err)
+		require.Len(t, cards, 2)
+		require.Equal(t, "lea", cards[0].Set)
+		require.Equal(t, "m10", cards[1].Set)
+	})
+
+	t.Run("reports API errors", func(t *testing.T) {
+		missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"object":"error","code":"not_found","status":404,"details":"No cards found"}`)
+		}))
+		defer missing.Close()
+
+		_, err := SearchCards(missing.URL)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Contains(t, err.Error(), "No cards found")
+	})
+}